@@ -10,6 +10,6 @@ var version = "dev"
 
 func main() {
 	if err := app.NewApp(version).Run(); err != nil {
-		os.Exit(1)
+		os.Exit(app.ExitCode(err))
 	}
 }
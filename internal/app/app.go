@@ -7,6 +7,7 @@ import (
 	"io/fs"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -14,25 +15,41 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/heartandu/easyrpc/internal/autocomplete"
+	"github.com/heartandu/easyrpc/internal/cmds"
 	"github.com/heartandu/easyrpc/internal/config"
+	"github.com/heartandu/easyrpc/internal/proto"
 )
 
 const (
 	defaultConfigName = ".easyrpc.yaml"
 
-	flagConfig     = "config"
-	flagAddress    = "address"
-	flagImportPath = "import-path"
-	flagProtoFile  = "proto-file"
-	flagReflection = "reflection"
-	flagWeb        = "web"
-	flagTLS        = "tls"
-	flagCACert     = "cacert"
-	flagCert       = "cert"
-	flagKey        = "key"
-	flagPackage    = "package"
-	flagService    = "service"
-	flagMetadata   = "metadata"
+	flagConfig         = "config"
+	flagAddress        = "address"
+	flagImportPath     = "import-path"
+	flagProtoFile      = "proto-file"
+	flagProtoset       = "protoset"
+	flagAutoDiscover   = "auto-discover"
+	flagCacheTTL       = "cache-ttl"
+	flagLayered        = "layered"
+	flagReflection     = "reflection"
+	flagReflectionAddr = "reflection-address"
+	flagWeb            = "web"
+	flagTLS            = "tls"
+	flagCACert         = "cacert"
+	flagCert           = "cert"
+	flagKey            = "key"
+	flagPackage        = "package"
+	flagService        = "service"
+	flagMetadata       = "metadata"
+	flagTimeout        = "timeout"
+	flagConnectTimeout = "connect-timeout"
+	flagFormat         = "format"
+	flagInputFormat    = "input-format"
+	flagProtoNames     = "use-proto-names"
+	flagEnumNumbers    = "use-enum-numbers"
+	flagUnpopulated    = "emit-unpopulated"
+	flagDiscardUnknown = "discard-unknown"
+	flagAllowPartial   = "allow-partial"
 )
 
 // App is a container of all application initialization and logic.
@@ -90,6 +107,7 @@ func (a *App) Run() error {
 	a.bindPFlagsToConfig()
 	a.bindEnv()
 	a.registerCommands()
+	reportUsageErrors(a.cmd)
 
 	cobra.OnInitialize(a.onInit)
 
@@ -107,16 +125,42 @@ func (a *App) bindPFlags() {
 		flagImportPath,
 		"i",
 		nil,
-		"proto import path, can provide multiple paths by repeating the flag",
+		"proto import path or .zip/.tar.gz archive, can provide multiple paths by repeating the flag",
 	)
 	a.pflags.StringSliceP(
 		flagProtoFile,
 		"p",
 		nil,
-		"proto files to use, can provide multiple files by repeating the flag",
+		"proto files or glob patterns like \"**/*.proto\" to use, can provide multiple files by repeating the flag",
 	)
 	a.cmd.RegisterFlagCompletionFunc(flagProtoFile, protoFileCompletion.Complete)
+	a.pflags.Bool(flagAutoDiscover, false, "use all proto files under the import paths, skipping broken ones")
+	a.pflags.StringSlice(
+		flagProtoset,
+		nil,
+		"protoset files with serialized FileDescriptorSet to use, can provide multiple files by repeating the flag",
+	)
 	a.pflags.BoolP(flagReflection, "r", false, "use server reflection to make requests")
+	a.pflags.String(
+		flagReflectionAddr,
+		"",
+		`server reflection host address in format "host:port", if it differs from the remote host address`,
+	)
+	a.pflags.String(
+		flagLayered,
+		"",
+		"use both server reflection and proto files or protosets, searching them in the order, one of: "+
+			strings.Join(proto.LayeredOrders, ", "),
+	)
+	a.cmd.RegisterFlagCompletionFunc(
+		flagLayered,
+		cobra.FixedCompletions(proto.LayeredOrders, cobra.ShellCompDirectiveNoFileComp),
+	)
+	a.pflags.Duration(
+		flagCacheTTL,
+		0,
+		"how long descriptors fetched with server reflection are cached, e.g. 10m, caching is disabled if not set",
+	)
 	a.pflags.BoolP(flagWeb, "w", false, "use gRPC-Web client to make requests")
 	a.pflags.Bool(flagTLS, false, "use a secure TLS connection")
 	a.pflags.String(flagCACert, "", "CA certificate file for verifying the server")
@@ -127,6 +171,23 @@ func (a *App) bindPFlags() {
 	a.pflags.String(flagService, "", "the service name to use as default")
 	a.cmd.RegisterFlagCompletionFunc(flagService, protoCompletion.CompleteService)
 	a.pflags.StringToStringP(flagMetadata, "H", nil, "default headers that are attached to every request")
+	a.pflags.Duration(flagTimeout, 0, "maximum duration of a call, e.g. 10s or 1m, no limit if not set")
+	a.pflags.Duration(flagConnectTimeout, 0, "maximum duration of establishing a connection, no limit if not set")
+	a.pflags.String(flagFormat, "json", "response output format, one of: "+strings.Join(cmds.OutputFormats, ", "))
+	a.cmd.RegisterFlagCompletionFunc(
+		flagFormat,
+		cobra.FixedCompletions(cmds.OutputFormats, cobra.ShellCompDirectiveNoFileComp),
+	)
+	a.pflags.String(flagInputFormat, "json", "request input format, one of: "+strings.Join(cmds.InputFormats, ", "))
+	a.cmd.RegisterFlagCompletionFunc(
+		flagInputFormat,
+		cobra.FixedCompletions(cmds.InputFormats, cobra.ShellCompDirectiveNoFileComp),
+	)
+	a.pflags.Bool(flagProtoNames, false, "use proto field names instead of lowerCamelCase names in JSON output")
+	a.pflags.Bool(flagEnumNumbers, false, "print enum values as numbers instead of names in JSON output")
+	a.pflags.Bool(flagUnpopulated, true, "print fields with default values in JSON output")
+	a.pflags.Bool(flagDiscardUnknown, false, "ignore unknown fields in request input instead of failing")
+	a.pflags.Bool(flagAllowPartial, false, "allow messages with missing required fields")
 }
 
 // bindPFlagsToConfig binds application global flags to configuration structure.
@@ -136,13 +197,27 @@ func (a *App) bindPFlagsToConfig() {
 	a.viper.BindPFlag("key", a.pflags.Lookup(flagKey))
 	a.viper.BindPFlag("address", a.pflags.Lookup(flagAddress))
 	a.viper.BindPFlag("reflection", a.pflags.Lookup(flagReflection))
+	a.viper.BindPFlag("reflection_address", a.pflags.Lookup(flagReflectionAddr))
+	a.viper.BindPFlag("cache_ttl", a.pflags.Lookup(flagCacheTTL))
+	a.viper.BindPFlag("layered", a.pflags.Lookup(flagLayered))
 	a.viper.BindPFlag("web", a.pflags.Lookup(flagWeb))
 	a.viper.BindPFlag("tls", a.pflags.Lookup(flagTLS))
 	a.viper.BindPFlag("import_paths", a.pflags.Lookup(flagImportPath))
 	a.viper.BindPFlag("proto_files", a.pflags.Lookup(flagProtoFile))
+	a.viper.BindPFlag("auto_discover", a.pflags.Lookup(flagAutoDiscover))
+	a.viper.BindPFlag("protosets", a.pflags.Lookup(flagProtoset))
 	a.viper.BindPFlag("package", a.pflags.Lookup(flagPackage))
 	a.viper.BindPFlag("service", a.pflags.Lookup(flagService))
 	a.viper.BindPFlag("metadata", a.pflags.Lookup(flagMetadata))
+	a.viper.BindPFlag("timeout", a.pflags.Lookup(flagTimeout))
+	a.viper.BindPFlag("connect_timeout", a.pflags.Lookup(flagConnectTimeout))
+	a.viper.BindPFlag("format", a.pflags.Lookup(flagFormat))
+	a.viper.BindPFlag("input_format", a.pflags.Lookup(flagInputFormat))
+	a.viper.BindPFlag("use_proto_names", a.pflags.Lookup(flagProtoNames))
+	a.viper.BindPFlag("use_enum_numbers", a.pflags.Lookup(flagEnumNumbers))
+	a.viper.BindPFlag("emit_unpopulated", a.pflags.Lookup(flagUnpopulated))
+	a.viper.BindPFlag("discard_unknown", a.pflags.Lookup(flagDiscardUnknown))
+	a.viper.BindPFlag("allow_partial", a.pflags.Lookup(flagAllowPartial))
 }
 
 func (a *App) bindEnv() {
@@ -155,6 +230,11 @@ func (a *App) registerCommands() {
 	a.registerCallCmd()
 	a.registerRequestCmd()
 	a.registerConfigCmd()
+	a.registerReplCmd()
+	a.registerListCmd()
+	a.registerDescribeCmd()
+	a.registerExportCmd()
+	a.registerCacheCmd()
 }
 
 func (a *App) onInit() {
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/heartandu/easyrpc/internal/cmds"
+)
+
+func (a *App) registerCacheCmd() {
+	cacheCmd := cmds.NewCache(a.fs)
+
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Descriptor cache manipulation",
+		Long: `Descriptors fetched with server reflection are cached for the time set with the --cache-ttl flag.
+The cache is separate for every address and TLS settings.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Remove all cached descriptors",
+		Args:  cobra.NoArgs,
+		RunE:  cacheCmd.Clear,
+	})
+
+	a.cmd.AddCommand(cmd)
+}
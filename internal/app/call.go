@@ -21,6 +21,9 @@ func (a *App) registerCallCmd() {
 	}
 
 	flags.RegisterDataFlag(cmd)
+	flags.RegisterVerboseFlag(cmd)
+	flags.RegisterEditFlag(cmd)
+	flags.RegisterDepthFlag(cmd)
 
 	a.cmd.AddCommand(cmd)
 }
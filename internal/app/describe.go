@@ -0,0 +1,24 @@
+package app
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/heartandu/easyrpc/internal/autocomplete"
+	"github.com/heartandu/easyrpc/internal/cmds"
+)
+
+func (a *App) registerDescribeCmd() {
+	describeCmd := cmds.NewDescribe(a.fs, &a.cfg)
+	symbolArgComp := autocomplete.NewProtoComp(a.fs, a.readConfig)
+
+	a.cmd.AddCommand(&cobra.Command{
+		Use:   "describe <symbol>",
+		Short: "Print the definition of a service, method, message or enum",
+		Long: `Print the definition of a service, method, message or enum in the proto syntax,
+including leading comments and options where available.
+The symbol name is relative to the --package flag, if it is set.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: symbolArgComp.CompleteSymbol,
+		RunE:              describeCmd.Run,
+	})
+}
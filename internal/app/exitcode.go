@@ -0,0 +1,68 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+
+	"github.com/heartandu/easyrpc/internal/cmds"
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+	"github.com/heartandu/easyrpc/pkg/format"
+	"github.com/heartandu/easyrpc/pkg/usecase"
+)
+
+// Process exit codes. Calls failed with a gRPC status exit with ExitCodeStatusBase increased by the status code,
+// e.g. 105 for NotFound or 114 for Unavailable.
+const (
+	ExitCodeOK           = 0
+	ExitCodeError        = 1
+	ExitCodeValidation   = 2
+	ExitCodeCompilation  = 3
+	ExitCodeInvalidInput = 4
+	ExitCodeStatusBase   = 100
+)
+
+// ExitCode returns the process exit code for the error returned by Run.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	switch {
+	case errors.Is(err, cmds.ErrValidation), errors.Is(err, cmds.ErrMissingArgs):
+		return ExitCodeValidation
+	case errors.Is(err, descriptor.ErrCompilation):
+		return ExitCodeCompilation
+	case errors.Is(err, format.ErrInvalidInput):
+		return ExitCodeInvalidInput
+	}
+
+	if st, ok := usecase.RPCStatus(err); ok && st.Code() != codes.OK {
+		return ExitCodeStatusBase + int(st.Code())
+	}
+
+	return ExitCodeError
+}
+
+// reportUsageErrors makes flag parsing errors and positional arguments errors of the command and its children
+// validation errors, so they exit with ExitCodeValidation.
+func reportUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return errors.Join(cmds.ErrValidation, err)
+	})
+
+	if validateArgs := cmd.Args; validateArgs != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validateArgs(cmd, args); err != nil {
+				return errors.Join(cmds.ErrValidation, err)
+			}
+
+			return nil
+		}
+	}
+
+	for _, c := range cmd.Commands() {
+		reportUsageErrors(c)
+	}
+}
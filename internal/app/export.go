@@ -0,0 +1,29 @@
+package app
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/heartandu/easyrpc/internal/cmds"
+	"github.com/heartandu/easyrpc/internal/flags"
+)
+
+func (a *App) registerExportCmd() {
+	exportCmd := cmds.NewExport(a.fs, &a.cfg)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export descriptors as a protoset or .proto files",
+		Long: `Export files defining all services of the descriptor source, along with the files they import.
+By default, a serialized FileDescriptorSet is written to stdout or to the --output file,
+which can be used with the --protoset flag. With --proto-dir, reconstructed .proto files are written
+into the directory instead, which can be used as an import path.`,
+		Args: cobra.NoArgs,
+		RunE: exportCmd.Run,
+	}
+
+	flags.RegisterOutputFlag(cmd)
+	flags.RegisterProtoDirFlag(cmd)
+	cmd.MarkFlagsMutuallyExclusive("output", "proto-dir")
+
+	a.cmd.AddCommand(cmd)
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/heartandu/easyrpc/internal/autocomplete"
+	"github.com/heartandu/easyrpc/internal/cmds"
+	"github.com/heartandu/easyrpc/internal/flags"
+)
+
+func (a *App) registerListCmd() {
+	listCmd := cmds.NewList(a.fs, &a.cfg)
+	serviceArgComp := autocomplete.NewProtoComp(a.fs, a.readConfig)
+
+	cmd := &cobra.Command{
+		Use:     "list [service]",
+		Aliases: []string{"ls"},
+		Short:   "List services or methods of a service",
+		Long: `List services, or methods of the service if it is provided as an argument or with the --service flag.
+Names are relative to the --package flag, if it is set.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: serviceArgComp.CompleteService,
+		RunE:              listCmd.Run,
+	}
+
+	flags.RegisterLongFlag(cmd)
+
+	a.cmd.AddCommand(cmd)
+}
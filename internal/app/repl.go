@@ -0,0 +1,21 @@
+package app
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/heartandu/easyrpc/internal/cmds"
+)
+
+func (a *App) registerReplCmd() {
+	replCmd := cmds.NewRepl(a.fs, &a.cfg)
+
+	a.cmd.AddCommand(&cobra.Command{
+		Use:   "repl",
+		Short: "Start an interactive session",
+		Long: `Start an interactive session that keeps a single connection and descriptor source
+for all calls. Type "help" inside the session for the list of available commands.
+Request data is always JSON, other input formats aren't supported.`,
+		Args: cobra.NoArgs,
+		RunE: replCmd.Run,
+	})
+}
@@ -13,14 +13,20 @@ func (a *App) registerRequestCmd() {
 	methodArgComp := autocomplete.NewProtoComp(a.fs, a.readConfig)
 
 	cmd := &cobra.Command{
-		Use:               "request [method]",
-		Aliases:           []string{"r"},
-		Short:             "Prepare a request for a method",
+		Use:     "request [method]",
+		Aliases: []string{"r"},
+		Short:   "Prepare a request for a method",
+		Long: `Prepare a request skeleton for a method with every field populated with a sample value.
+Nested messages are populated up to the --depth limit, repeated fields and maps get one sample element.
+The printed skeleton is plain JSON. With --edit, allowed enum values and oneof alternatives are listed
+in line comments in the editor, which are removed from the printed request.
+Use the describe command to see them without the editor.`,
 		ValidArgsFunction: methodArgComp.CompleteMethod,
 		RunE:              requestCmd.Run,
 	}
 
 	flags.RegisterEditFlag(cmd)
+	flags.RegisterDepthFlag(cmd)
 	flags.RegisterOutputFlag(cmd)
 
 	a.cmd.AddCommand(cmd)
@@ -3,6 +3,7 @@ package autocomplete
 import (
 	"context"
 	"iter"
+	"slices"
 	"strings"
 
 	"github.com/spf13/afero"
@@ -11,6 +12,7 @@ import (
 	"github.com/heartandu/easyrpc/internal/client"
 	"github.com/heartandu/easyrpc/internal/config"
 	"github.com/heartandu/easyrpc/internal/proto"
+	"github.com/heartandu/easyrpc/pkg/descriptor"
 )
 
 const delim = "."
@@ -161,28 +163,82 @@ func (c *ProtoComp) CompleteService(
 	return services, cobra.ShellCompDirectiveNoFileComp
 }
 
+// CompleteSymbol provides autocomplete suggestions for names of services, methods, messages and enums.
+func (c *ProtoComp) CompleteSymbol(
+	_ *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cfg, err := c.cfgFunc()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	descSrc, err := c.descriptorSource(&cfg)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	symbols, err := descriptor.ListSymbols(descSrc)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	// If package name has been set, only the symbols of the package
+	// are suggested, and the package name is omitted from them.
+	if cfg.Request.Package != "" {
+		prefix := cfg.Request.Package + delim
+		relative := make([]string, 0, len(symbols))
+
+		for _, symbol := range symbols {
+			if name, ok := strings.CutPrefix(symbol, prefix); ok {
+				relative = append(relative, name)
+			}
+		}
+
+		symbols = relative
+	}
+
+	return matchSymbols(slices.Values(symbols), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
 func (c *ProtoComp) symbols(
 	cfg *config.Config,
 	toComplete string,
 	filterMapFunc func(pkg, svc, method string) string,
 ) ([]string, error) {
-	ctx := context.Background()
-
-	cc, err := client.New(c.fs, cfg)
+	descSrc, err := c.descriptorSource(cfg)
 	if err != nil {
-		return nil, err //nolint:wrapcheck // Error wrapping is unnecessary in authocomplete.
+		return nil, err
 	}
 
-	descSrc, err := proto.NewDescriptorSource(ctx, c.fs, cfg, cc)
+	methods, err := descSrc.ListMethods()
 	if err != nil {
 		return nil, err //nolint:wrapcheck // Error wrapping is unnecessary in authocomplete.
 	}
 
-	methods, err := descSrc.ListMethods()
+	return matchSymbols(filterMapIter(methods, filterMapFunc), toComplete), nil
+}
+
+func (c *ProtoComp) descriptorSource(cfg *config.Config) (descriptor.Source, error) {
+	ctx := context.Background()
+
+	cc, err := client.New(ctx, c.fs, cfg)
 	if err != nil {
 		return nil, err //nolint:wrapcheck // Error wrapping is unnecessary in authocomplete.
 	}
 
+	//nolint:wrapcheck // Error wrapping is unnecessary in authocomplete.
+	return proto.NewDescriptorSource(ctx, c.fs, cfg, cc)
+}
+
+// matchSymbols returns unique symbols containing the completion.
+// The match is case-insensitive, unless the completion contains upper case letters.
+func matchSymbols(symbols iter.Seq[string], toComplete string) []string {
 	encounteredSymbols := map[string]struct{}{}
 	result := make([]string, 0)
 
@@ -193,7 +249,7 @@ func (c *ProtoComp) symbols(
 		completionToCompare = strings.ToLower(completionToCompare)
 	}
 
-	for symbol := range filterMapIter(methods, filterMapFunc) {
+	for symbol := range symbols {
 		symbolToCompare := symbol
 
 		if isCaseInsensitive {
@@ -209,7 +265,7 @@ func (c *ProtoComp) symbols(
 		}
 	}
 
-	return result, nil
+	return result
 }
 
 func filterMapIter(s []string, f func(pkg, svc, method string) string) iter.Seq[string] {
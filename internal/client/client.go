@@ -1,14 +1,20 @@
 package client
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/heartandu/grpc-web-go-client/grpcweb"
 	"github.com/spf13/afero"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"github.com/heartandu/easyrpc/internal/config"
 	"github.com/heartandu/easyrpc/pkg/conn"
@@ -17,12 +23,47 @@ import (
 
 // New creates a new gRPC client connection based on the provided configuration.
 // It checks the configuration to determine whether to establish a gRPC or gRPC-Web connection.
-func New(fs afero.Fs, cfg *config.Config) (grpc.ClientConnInterface, error) {
+// If the connect timeout is configured, a gRPC connection is established eagerly and
+// an error is returned if it's not ready in time. gRPC-Web connections are always established lazily.
+func New(ctx context.Context, fs afero.Fs, cfg *config.Config) (grpc.ClientConnInterface, error) {
 	if cfg.Server.Web {
 		return clientWebConn(fs, cfg)
 	}
 
-	return clientGRPCConn(fs, cfg)
+	cc, err := clientGRPCConn(fs, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Server.ConnectTimeout > 0 {
+		if err := waitForReady(ctx, cc, cfg.Server.ConnectTimeout); err != nil {
+			cc.Close() //nolint:errcheck,gosec // The connection error is more informative.
+
+			return nil, err
+		}
+	}
+
+	return cc, nil
+}
+
+// waitForReady starts connecting and waits until the connection is ready or the timeout is exceeded.
+func waitForReady(ctx context.Context, cc *grpc.ClientConn, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cc.Connect()
+
+	for state := cc.GetState(); state != connectivity.Ready; state = cc.GetState() {
+		if !cc.WaitForStateChange(ctx, state) {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return status.Errorf(codes.DeadlineExceeded, "failed to connect to %q within %s", cc.Target(), timeout)
+			}
+
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+
+	return nil
 }
 
 // clientGRPCConn creates a new gRPC client connection.
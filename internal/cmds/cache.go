@@ -0,0 +1,31 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/heartandu/easyrpc/internal/proto"
+)
+
+// Cache represents a command to manage the descriptor cache.
+type Cache struct {
+	fs afero.Fs
+}
+
+// NewCache creates a new Cache command.
+func NewCache(fs afero.Fs) *Cache {
+	return &Cache{
+		fs: fs,
+	}
+}
+
+// Clear removes all cached descriptors.
+func (c *Cache) Clear(_ *cobra.Command, _ []string) error {
+	if err := proto.ClearCache(c.fs); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+
+	return nil
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
 
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -14,6 +17,8 @@ import (
 	"github.com/heartandu/easyrpc/internal/config"
 	"github.com/heartandu/easyrpc/internal/flags"
 	"github.com/heartandu/easyrpc/internal/proto"
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+	"github.com/heartandu/easyrpc/pkg/editor"
 	"github.com/heartandu/easyrpc/pkg/format"
 	"github.com/heartandu/easyrpc/pkg/fqn"
 	"github.com/heartandu/easyrpc/pkg/usecase"
@@ -39,7 +44,11 @@ func (c *Call) Run(cmd *cobra.Command, args []string) error {
 		return ErrMissingArgs
 	}
 
-	if err := c.validateConfig(); err != nil {
+	if err := validateConnConfig(c.cfg); err != nil {
+		return errors.Join(ErrValidation, err)
+	}
+
+	if err := validateFormatConfig(c.cfg); err != nil {
 		return errors.Join(ErrValidation, err)
 	}
 
@@ -49,9 +58,28 @@ func (c *Call) Run(cmd *cobra.Command, args []string) error {
 	}
 	defer input.Close()
 
-	ctx := context.Background()
+	verboseOut, err := flags.HandleVerboseFlag(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to handle verbose flag: %w", err)
+	}
+
+	e, err := flags.HandleEditFlag(cmd, c.fs, c.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to handle edit flag: %w", err)
+	}
+
+	if e != nil && c.cfg.Format.Input == formatBinary {
+		return errors.Join(ErrValidation, ErrEditBinaryInput)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Until the call is made, an interrupt cancels the connection and the descriptor source setup.
+	interrupts := handleInterrupts(cancel)
+	defer interrupts.Stop()
 
-	cc, err := client.New(c.fs, c.cfg)
+	cc, err := client.New(ctx, c.fs, c.cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create client connection: %w", err)
 	}
@@ -61,33 +89,164 @@ func (c *Call) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create descriptor source: %w", err)
 	}
 
-	mp := format.JSONMessageParser(input, protojson.UnmarshalOptions{})
-	mf := format.JSONMessageFormatter(protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true})
+	resolver := descriptor.NewTypeResolver(descSrc)
+
+	newParser, err := messageParser(c.cfg, resolver)
+	if err != nil {
+		return errors.Join(ErrValidation, err)
+	}
 
-	call := usecase.NewCall(cmd.OutOrStdout(), descSrc, cc, mp, mf, metadata.New(c.cfg.Request.Metadata))
+	method := fqn.FullyQualifiedMethodName(args[0], c.cfg.Request.Package, c.cfg.Request.Service)
 
-	err = call.MakeRPCCall(ctx, fqn.FullyQualifiedMethodName(args[0], c.cfg.Request.Package, c.cfg.Request.Service))
+	var data io.Reader = input
+
+	if e != nil {
+		data, err = c.editRequest(cmd, e, descSrc, newParser, input, method)
+		if err != nil {
+			return fmt.Errorf("failed to edit request: %w", err)
+		}
+	}
+
+	mf, err := messageFormatter(c.cfg, resolver)
 	if err != nil {
+		return errors.Join(ErrValidation, err)
+	}
+
+	call := usecase.NewCall(
+		cmd.OutOrStdout(),
+		verboseOut,
+		descSrc,
+		cc,
+		newParser(data),
+		mf,
+		metadata.New(c.cfg.Request.Metadata),
+		c.cfg.Request.Timeout,
+	)
+
+	interrupts.Set(call.Interrupt)
+
+	err = call.MakeRPCCall(ctx, method)
+	if err != nil {
+		printStatus(cmd.ErrOrStderr(), statusFormatter(descSrc), err)
+
 		return fmt.Errorf("call rpc failed: %w", err)
 	}
 
 	return nil
 }
 
-func (c *Call) validateConfig() error {
+// editRequest opens the request data in the editor, or a request skeleton of the method if there is no data,
+// and returns the edited data once it's valid. Skeletons are JSON, so for other input formats the data is required.
+func (c *Call) editRequest(
+	cmd *cobra.Command,
+	e editor.Editor,
+	ds descriptor.Source,
+	newParser usecase.ParserFunc,
+	input io.Reader,
+	method string,
+) (io.Reader, error) {
+	m, err := ds.FindMethod(method)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find method: %w", err)
+	}
+
+	b, err := io.ReadAll(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request data: %w", err)
+	}
+
+	msg := string(b)
+
+	if strings.TrimSpace(msg) == "" {
+		if c.cfg.Format.Input != "" && c.cfg.Format.Input != formatJSON {
+			return nil, errors.Join(ErrValidation, ErrEditEmptyInput)
+		}
+
+		depth, err := flags.HandleDepthFlag(cmd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to handle depth flag: %w", err)
+		}
+
+		msg = format.JSONSkeleton(m.RequestMessage().ProtoReflect().Descriptor(), format.SkeletonOptions{
+			MaxDepth:      depth,
+			UseProtoNames: c.cfg.Format.UseProtoNames,
+			Comments:      true,
+		})
+	}
+
+	msg, err = usecase.EditRequest(e, newParser, commentPrefix(c.cfg), m.RequestMessage(), msg)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // The error is wrapped by the caller.
+	}
+
+	return strings.NewReader(msg), nil
+}
+
+// validateConnConfig validates the configuration required to connect to a server and to describe its methods.
+func validateConnConfig(cfg *config.Config) error {
 	var err error
 
-	if c.cfg.TLS.Cert == "" && c.cfg.TLS.Key != "" || c.cfg.TLS.Cert != "" && c.cfg.TLS.Key == "" {
+	if cfg.TLS.Cert == "" && cfg.TLS.Key != "" || cfg.TLS.Cert != "" && cfg.TLS.Key == "" {
 		err = errors.Join(err, ErrMissingCertOrKey)
 	}
 
-	if c.cfg.Server.Address == "" {
+	if cfg.Server.Address == "" {
 		err = errors.Join(err, ErrEmptyAddress)
 	}
 
-	if len(c.cfg.Proto.ProtoFiles) == 0 && !c.cfg.Server.Reflection {
+	if !hasLocalSource(cfg) && !cfg.Server.Reflection {
 		err = errors.Join(err, ErrNoSource)
 	}
 
-	return err
+	return errors.Join(err, validateLayeredConfig(cfg))
+}
+
+// hasLocalSource returns true if proto files or protosets are configured.
+func hasLocalSource(cfg *config.Config) bool {
+	return len(cfg.Proto.ProtoFiles) > 0 || len(cfg.Proto.Protosets) > 0 || cfg.Proto.AutoDiscover
+}
+
+// validateLayeredConfig validates the configuration of the layered source, if it's used.
+func validateLayeredConfig(cfg *config.Config) error {
+	if cfg.Proto.Layered == "" {
+		return nil
+	}
+
+	if !slices.Contains(proto.LayeredOrders, cfg.Proto.Layered) {
+		return fmt.Errorf(
+			"%w %q, must be one of: %s",
+			ErrUnknownLayered,
+			cfg.Proto.Layered,
+			strings.Join(proto.LayeredOrders, ", "),
+		)
+	}
+
+	if !hasLocalSource(cfg) {
+		return ErrNoLocalSource
+	}
+
+	return nil
+}
+
+// statusFormatter returns a formatter of failed calls statuses, which resolves details types with the source.
+func statusFormatter(descSrc descriptor.Source) format.StatusFormatter {
+	return format.JSONStatusFormatter(protojson.MarshalOptions{
+		Multiline: true,
+		Resolver:  descriptor.NewTypeResolver(descSrc),
+	})
+}
+
+// printStatus writes the formatted gRPC status if the error carries one.
+func printStatus(w io.Writer, sf format.StatusFormatter, err error) {
+	st, ok := usecase.RPCStatus(err)
+	if !ok {
+		return
+	}
+
+	formattedStatus, err := sf.Format(st)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprint(w, formattedStatus)
 }
@@ -0,0 +1,57 @@
+package cmds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/heartandu/easyrpc/internal/client"
+	"github.com/heartandu/easyrpc/internal/config"
+	"github.com/heartandu/easyrpc/internal/proto"
+	"github.com/heartandu/easyrpc/pkg/usecase"
+)
+
+// Describe represents a command to print definitions of protobuf symbols.
+type Describe struct {
+	fs  afero.Fs
+	cfg *config.Config
+}
+
+// NewDescribe creates a new Describe command.
+func NewDescribe(fs afero.Fs, cfg *config.Config) *Describe {
+	return &Describe{
+		fs:  fs,
+		cfg: cfg,
+	}
+}
+
+// Run executes the Describe command.
+func (d *Describe) Run(cmd *cobra.Command, args []string) error {
+	if err := validateSourceConfig(d.cfg); err != nil {
+		return errors.Join(ErrValidation, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cc, err := client.New(ctx, d.fs, d.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client connection: %w", err)
+	}
+
+	descSrc, err := proto.NewDescriptorSource(ctx, d.fs, d.cfg, cc)
+	if err != nil {
+		return fmt.Errorf("failed to create descriptor source: %w", err)
+	}
+
+	if err := usecase.NewDescribe(cmd.OutOrStdout(), descSrc).Describe(d.cfg.Request.Package, args[0]); err != nil {
+		return fmt.Errorf("describe failed: %w", err)
+	}
+
+	return nil
+}
@@ -7,5 +7,11 @@ var (
 	ErrValidation       = errors.New("validation failed")
 	ErrMissingCertOrKey = errors.New("cert and key must be both set")
 	ErrEmptyAddress     = errors.New("address must not be empty")
-	ErrNoSource         = errors.New("at least 1 proto file must be specified or reflection used")
+	ErrNoSource         = errors.New("proto files, protosets, auto discovery or reflection must be used")
+	ErrUnknownFormat    = errors.New("unknown format")
+	ErrUnknownLayered   = errors.New("unknown layered source order")
+	ErrNoLocalSource    = errors.New("proto files, protosets or auto discovery must be used for the layered source")
+	ErrEditBinaryInput  = errors.New("binary input can't be edited")
+	ErrEditEmptyInput   = errors.New("request skeletons are only generated for json input, provide the data to edit")
+	ErrReplInputFormat  = errors.New("repl only supports json input")
 )
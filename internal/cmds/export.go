@@ -0,0 +1,84 @@
+package cmds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/heartandu/easyrpc/internal/client"
+	"github.com/heartandu/easyrpc/internal/config"
+	"github.com/heartandu/easyrpc/internal/flags"
+	"github.com/heartandu/easyrpc/internal/proto"
+	"github.com/heartandu/easyrpc/pkg/usecase"
+)
+
+// Export represents a command to save descriptors for offline usage.
+type Export struct {
+	fs  afero.Fs
+	cfg *config.Config
+}
+
+// NewExport creates a new Export command.
+func NewExport(fs afero.Fs, cfg *config.Config) *Export {
+	return &Export{
+		fs:  fs,
+		cfg: cfg,
+	}
+}
+
+// Run executes the Export command.
+// It writes a protoset to the output, unless a directory for .proto files is provided.
+func (e *Export) Run(cmd *cobra.Command, _ []string) error {
+	if err := validateSourceConfig(e.cfg); err != nil {
+		return errors.Join(ErrValidation, err)
+	}
+
+	protoDir, err := flags.HandleProtoDirFlag(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to handle proto dir flag: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cc, err := client.New(ctx, e.fs, e.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client connection: %w", err)
+	}
+
+	// An export is a snapshot of the live server, so cached descriptors are never used.
+	cfg := *e.cfg
+	cfg.Cache.TTL = 0
+
+	descSrc, err := proto.NewDescriptorSource(ctx, e.fs, &cfg, cc)
+	if err != nil {
+		return fmt.Errorf("failed to create descriptor source: %w", err)
+	}
+
+	export := usecase.NewExport(descSrc)
+
+	if protoDir != "" {
+		if err := export.ProtoFiles(e.fs, protoDir); err != nil {
+			return fmt.Errorf("export proto files failed: %w", err)
+		}
+
+		return nil
+	}
+
+	out, err := flags.HandleOutputFlag(cmd, e.fs)
+	if err != nil {
+		return fmt.Errorf("failed to handle output flag: %w", err)
+	}
+	defer out.Close()
+
+	if err := export.Protoset(out); err != nil {
+		return fmt.Errorf("export protoset failed: %w", err)
+	}
+
+	return nil
+}
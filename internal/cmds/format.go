@@ -0,0 +1,147 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/heartandu/easyrpc/internal/config"
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+	"github.com/heartandu/easyrpc/pkg/format"
+	"github.com/heartandu/easyrpc/pkg/usecase"
+)
+
+const (
+	formatJSON    = "json"
+	formatCompact = "compact"
+	formatNDJSON  = "ndjson"
+	formatText    = "text"
+	formatYAML    = "yaml"
+	formatBinary  = "binary"
+)
+
+var (
+	// OutputFormats is a list of supported response output formats.
+	OutputFormats = []string{formatJSON, formatCompact, formatNDJSON, formatText, formatYAML, formatBinary}
+	// InputFormats is a list of supported request input formats.
+	InputFormats = []string{formatJSON, formatText, formatYAML, formatBinary}
+)
+
+// validateFormatConfig validates the configured input and output format names.
+func validateFormatConfig(cfg *config.Config) error {
+	var err error
+
+	if name := cfg.Format.Output; name != "" && !slices.Contains(OutputFormats, name) {
+		err = errors.Join(err, unknownFormatErr(name, OutputFormats))
+	}
+
+	if name := cfg.Format.Input; name != "" && !slices.Contains(InputFormats, name) {
+		err = errors.Join(err, unknownFormatErr(name, InputFormats))
+	}
+
+	return err
+}
+
+func unknownFormatErr(name string, formats []string) error {
+	return fmt.Errorf("%w %q, must be one of: %s", ErrUnknownFormat, name, strings.Join(formats, ", "))
+}
+
+// messageFormatter returns a response message formatter for the configured output format.
+// Empty format name stands for the default JSON format.
+// Message types of google.protobuf.Any fields and extensions are resolved with the provided resolver.
+func messageFormatter(cfg *config.Config, resolver descriptor.Resolver) (format.MessageFormatter, error) {
+	jsonOpts := marshalOptions(cfg, resolver)
+
+	switch name := cfg.Format.Output; name {
+	case "", formatJSON:
+		jsonOpts.Multiline = true
+
+		return format.JSONMessageFormatter(jsonOpts), nil
+	case formatCompact, formatNDJSON:
+		// Compact JSON messages never span multiple lines, so the output is newline-delimited JSON as well.
+		return format.JSONMessageFormatter(jsonOpts), nil
+	case formatText:
+		return format.TextMessageFormatter(prototext.MarshalOptions{
+			Multiline:    true,
+			AllowPartial: cfg.Format.AllowPartial,
+			EmitUnknown:  !cfg.Format.DiscardUnknown,
+			Resolver:     resolver,
+		}), nil
+	case formatYAML:
+		return format.YAMLMessageFormatter(jsonOpts), nil
+	case formatBinary:
+		return format.BinaryMessageFormatter(proto.MarshalOptions{AllowPartial: cfg.Format.AllowPartial}), nil
+	default:
+		return nil, unknownFormatErr(name, OutputFormats)
+	}
+}
+
+// messageParser returns a function creating request message parsers, which read the input in the configured
+// input format. Empty format name stands for the default JSON format.
+// Message types of google.protobuf.Any fields and extensions are resolved with the provided resolver.
+func messageParser(cfg *config.Config, resolver descriptor.Resolver) (usecase.ParserFunc, error) {
+	switch name := cfg.Format.Input; name {
+	case "", formatJSON:
+		return func(input io.Reader) format.MessageParser {
+			return format.JSONMessageParser(input, unmarshalOptions(cfg, resolver))
+		}, nil
+	case formatText:
+		return func(input io.Reader) format.MessageParser {
+			return format.TextMessageParser(input, prototext.UnmarshalOptions{
+				AllowPartial:   cfg.Format.AllowPartial,
+				DiscardUnknown: cfg.Format.DiscardUnknown,
+				Resolver:       resolver,
+			})
+		}, nil
+	case formatYAML:
+		return func(input io.Reader) format.MessageParser {
+			return format.YAMLMessageParser(input, unmarshalOptions(cfg, resolver))
+		}, nil
+	case formatBinary:
+		return func(input io.Reader) format.MessageParser {
+			return format.BinaryMessageParser(input, proto.UnmarshalOptions{
+				AllowPartial:   cfg.Format.AllowPartial,
+				DiscardUnknown: cfg.Format.DiscardUnknown,
+				Resolver:       resolver,
+			})
+		}, nil
+	default:
+		return nil, unknownFormatErr(name, InputFormats)
+	}
+}
+
+// commentPrefix returns the prefix of line comments of the configured input format.
+func commentPrefix(cfg *config.Config) string {
+	switch cfg.Format.Input {
+	case formatText, formatYAML:
+		return "#"
+	default:
+		return "//"
+	}
+}
+
+// marshalOptions returns the configured protobuf JSON marshalling options.
+func marshalOptions(cfg *config.Config, resolver descriptor.Resolver) protojson.MarshalOptions {
+	return protojson.MarshalOptions{
+		UseProtoNames:   cfg.Format.UseProtoNames,
+		UseEnumNumbers:  cfg.Format.UseEnumNumbers,
+		EmitUnpopulated: cfg.Format.EmitUnpopulated,
+		AllowPartial:    cfg.Format.AllowPartial,
+		Resolver:        resolver,
+	}
+}
+
+// unmarshalOptions returns the configured protobuf JSON unmarshalling options.
+func unmarshalOptions(cfg *config.Config, resolver descriptor.Resolver) protojson.UnmarshalOptions {
+	return protojson.UnmarshalOptions{
+		AllowPartial:   cfg.Format.AllowPartial,
+		DiscardUnknown: cfg.Format.DiscardUnknown,
+		Resolver:       resolver,
+	}
+}
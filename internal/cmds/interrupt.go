@@ -0,0 +1,55 @@
+package cmds
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// interruptHandler dispatches interrupt signals to the current handling function.
+type interruptHandler struct {
+	mu   sync.Mutex
+	fn   func()
+	sig  chan os.Signal
+	done chan struct{}
+}
+
+// handleInterrupts starts calling fn on every interrupt signal until the handler is stopped.
+func handleInterrupts(fn func()) *interruptHandler {
+	h := &interruptHandler{
+		fn:   fn,
+		sig:  make(chan os.Signal, 1),
+		done: make(chan struct{}),
+	}
+
+	signal.Notify(h.sig, os.Interrupt)
+
+	go func() {
+		for {
+			select {
+			case <-h.sig:
+				h.mu.Lock()
+				h.fn()
+				h.mu.Unlock()
+			case <-h.done:
+				return
+			}
+		}
+	}()
+
+	return h
+}
+
+// Set replaces the function called on interrupt signals.
+func (h *interruptHandler) Set(fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.fn = fn
+}
+
+// Stop restores the default behavior of interrupt signals.
+func (h *interruptHandler) Stop() {
+	signal.Stop(h.sig)
+	close(h.done)
+}
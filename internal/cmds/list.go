@@ -0,0 +1,80 @@
+package cmds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/heartandu/easyrpc/internal/client"
+	"github.com/heartandu/easyrpc/internal/config"
+	"github.com/heartandu/easyrpc/internal/flags"
+	"github.com/heartandu/easyrpc/internal/proto"
+	"github.com/heartandu/easyrpc/pkg/usecase"
+)
+
+// List represents a command to list services and methods.
+type List struct {
+	fs  afero.Fs
+	cfg *config.Config
+}
+
+// NewList creates a new List command.
+func NewList(fs afero.Fs, cfg *config.Config) *List {
+	return &List{
+		fs:  fs,
+		cfg: cfg,
+	}
+}
+
+// Run executes the List command.
+// Without arguments, it lists services, unless the default service is configured.
+// Otherwise, it lists methods of the given or the default service.
+func (l *List) Run(cmd *cobra.Command, args []string) error {
+	if err := validateSourceConfig(l.cfg); err != nil {
+		return errors.Join(ErrValidation, err)
+	}
+
+	long, err := flags.HandleLongFlag(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to handle long flag: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cc, err := client.New(ctx, l.fs, l.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client connection: %w", err)
+	}
+
+	descSrc, err := proto.NewDescriptorSource(ctx, l.fs, l.cfg, cc)
+	if err != nil {
+		return fmt.Errorf("failed to create descriptor source: %w", err)
+	}
+
+	list := usecase.NewList(cmd.OutOrStdout(), descSrc, long)
+
+	service := l.cfg.Request.Service
+	if len(args) > 0 {
+		service = args[0]
+	}
+
+	if service == "" {
+		if err := list.Services(l.cfg.Request.Package); err != nil {
+			return fmt.Errorf("list services failed: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := list.Methods(l.cfg.Request.Package, service); err != nil {
+		return fmt.Errorf("list methods failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package cmds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/chzyer/readline"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/heartandu/easyrpc/internal/client"
+	"github.com/heartandu/easyrpc/internal/config"
+	"github.com/heartandu/easyrpc/internal/proto"
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+	"github.com/heartandu/easyrpc/pkg/format"
+	"github.com/heartandu/easyrpc/pkg/usecase"
+)
+
+// Repl represents a command to run an interactive session.
+type Repl struct {
+	fs  afero.Fs
+	cfg *config.Config
+}
+
+// NewRepl creates a new Repl command.
+func NewRepl(fs afero.Fs, cfg *config.Config) *Repl {
+	return &Repl{
+		fs:  fs,
+		cfg: cfg,
+	}
+}
+
+// Run executes the Repl command.
+func (r *Repl) Run(cmd *cobra.Command, _ []string) error {
+	if err := validateConnConfig(r.cfg); err != nil {
+		return errors.Join(ErrValidation, err)
+	}
+
+	if err := validateFormatConfig(r.cfg); err != nil {
+		return errors.Join(ErrValidation, err)
+	}
+
+	// Request data is typed in a single line, which only suits JSON.
+	if name := r.cfg.Format.Input; name != "" && name != formatJSON {
+		return errors.Join(ErrValidation, ErrReplInputFormat)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Until the session starts, an interrupt cancels the connection and the descriptor source setup.
+	interrupts := handleInterrupts(cancel)
+	defer interrupts.Stop()
+
+	cc, err := client.New(ctx, r.fs, r.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client connection: %w", err)
+	}
+
+	descSrc, err := proto.NewDescriptorSource(ctx, r.fs, r.cfg, cc)
+	if err != nil {
+		return fmt.Errorf("failed to create descriptor source: %w", err)
+	}
+
+	resolver := descriptor.NewTypeResolver(descSrc)
+
+	mf, err := messageFormatter(r.cfg, resolver)
+	if err != nil {
+		return errors.Join(ErrValidation, err)
+	}
+
+	rl, err := readline.NewEx(&readline.Config{
+		Stdin:           io.NopCloser(cmd.InOrStdin()),
+		Stdout:          cmd.OutOrStdout(),
+		Stderr:          cmd.ErrOrStderr(),
+		InterruptPrompt: "^C",
+		EOFPrompt:       "exit",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create line reader: %w", err)
+	}
+	defer rl.Close()
+
+	newParser := func(input io.Reader) format.MessageParser {
+		return format.JSONMessageParser(input, unmarshalOptions(r.cfg, resolver))
+	}
+
+	repl := usecase.NewRepl(
+		cmd.OutOrStdout(),
+		&lineReader{rl: rl},
+		descSrc,
+		cc,
+		newParser,
+		mf,
+		statusFormatter(descSrc),
+		metadata.New(r.cfg.Request.Metadata),
+		r.cfg.Request.Timeout,
+		r.cfg.Request.Package,
+		r.cfg.Request.Service,
+	)
+
+	rl.Config.AutoComplete = readline.NewPrefixCompleter(
+		readline.PcItem("call", readline.PcItemDynamic(repl.CompleteMethod)),
+		readline.PcItem("list"),
+		readline.PcItem("package", readline.PcItemDynamic(repl.CompletePackage)),
+		readline.PcItem("service", readline.PcItemDynamic(repl.CompleteService)),
+		readline.PcItem("help"),
+		readline.PcItem("exit"),
+		readline.PcItem("quit"),
+	)
+
+	interrupts.Set(func() {
+		// Without an ongoing call, an interrupt ends the session the same way the end of input does.
+		if !repl.Interrupt() {
+			rl.Close()
+		}
+	})
+
+	if err := repl.Run(ctx); err != nil {
+		return fmt.Errorf("repl failed: %w", err)
+	}
+
+	return nil
+}
+
+// lineReader is an adapter of readline.Instance that discards interrupted lines instead of failing.
+type lineReader struct {
+	rl *readline.Instance
+}
+
+// Readline reads a line of user input.
+func (r *lineReader) Readline() (string, error) {
+	return r.handleInterrupt(r.rl.Readline())
+}
+
+// ReadlineWithDefault reads a line of user input with a pre-filled editable value.
+func (r *lineReader) ReadlineWithDefault(what string) (string, error) {
+	return r.handleInterrupt(r.rl.ReadlineWithDefault(what))
+}
+
+// SetPrompt changes the prompt shown to the user.
+func (r *lineReader) SetPrompt(prompt string) {
+	r.rl.SetPrompt(prompt)
+}
+
+func (*lineReader) handleInterrupt(line string, err error) (string, error) {
+	if errors.Is(err, readline.ErrInterrupt) {
+		return "", nil
+	}
+
+	return line, err //nolint:wrapcheck // This is a simple decorator.
+}
@@ -4,15 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
 
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
-	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/heartandu/easyrpc/internal/client"
 	"github.com/heartandu/easyrpc/internal/config"
 	"github.com/heartandu/easyrpc/internal/flags"
 	"github.com/heartandu/easyrpc/internal/proto"
+	"github.com/heartandu/easyrpc/pkg/descriptor"
 	"github.com/heartandu/easyrpc/pkg/format"
 	"github.com/heartandu/easyrpc/pkg/fqn"
 	"github.com/heartandu/easyrpc/pkg/usecase"
@@ -38,13 +41,14 @@ func (r *Request) Run(cmd *cobra.Command, args []string) error {
 		return ErrMissingArgs
 	}
 
-	if err := r.validateConfig(); err != nil {
+	if err := validateSourceConfig(r.cfg); err != nil {
 		return errors.Join(ErrValidation, err)
 	}
 
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	cc, err := client.New(r.fs, r.cfg)
+	cc, err := client.New(ctx, r.fs, r.cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create client connection: %w", err)
 	}
@@ -65,8 +69,22 @@ func (r *Request) Run(cmd *cobra.Command, args []string) error {
 	}
 	defer out.Close()
 
-	mf := format.JSONMessageFormatter(protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true})
-	request := usecase.NewRequest(out, e, r.fs, ds, mf)
+	depth, err := flags.HandleDepthFlag(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to handle depth flag: %w", err)
+	}
+
+	resolver := descriptor.NewTypeResolver(ds)
+
+	// Request skeletons are always JSON, so the edited requests are parsed as JSON regardless of the input format.
+	newParser := func(input io.Reader) format.MessageParser {
+		return format.JSONMessageParser(input, unmarshalOptions(r.cfg, resolver))
+	}
+
+	request := usecase.NewRequest(out, e, r.fs, ds, newParser, format.SkeletonOptions{
+		MaxDepth:      depth,
+		UseProtoNames: r.cfg.Format.UseProtoNames,
+	})
 
 	err = request.Prepare(fqn.FullyQualifiedMethodName(args[0], r.cfg.Request.Package, r.cfg.Request.Service))
 	if err != nil {
@@ -76,22 +94,24 @@ func (r *Request) Run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func (r *Request) validateConfig() error {
+// validateSourceConfig validates the configuration required to describe methods without calling them.
+// Unlike validateConnConfig, the server address is only required for server reflection and the layered source.
+func validateSourceConfig(cfg *config.Config) error {
 	var err error
 
-	if len(r.cfg.Proto.ProtoFiles) == 0 && !r.cfg.Server.Reflection {
+	if !hasLocalSource(cfg) && !cfg.Server.Reflection {
 		err = errors.Join(err, ErrNoSource)
 	}
 
-	if r.cfg.Server.Reflection {
-		if r.cfg.Server.Address == "" {
+	if cfg.Server.Reflection || cfg.Proto.Layered != "" {
+		if cfg.Server.Address == "" && cfg.Server.ReflectionAddress == "" {
 			err = errors.Join(err, ErrEmptyAddress)
 		}
 
-		if r.cfg.TLS.Cert == "" && r.cfg.TLS.Key != "" || r.cfg.TLS.Cert != "" && r.cfg.TLS.Key == "" {
+		if cfg.TLS.Cert == "" && cfg.TLS.Key != "" || cfg.TLS.Cert != "" && cfg.TLS.Key == "" {
 			err = errors.Join(err, ErrMissingCertOrKey)
 		}
 	}
 
-	return err
+	return errors.Join(err, validateLayeredConfig(cfg))
 }
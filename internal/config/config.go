@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // Config represents a common cross-application configuration.
 type Config struct {
 	Proto   proto   `mapstructure:",squash"`
@@ -7,19 +9,26 @@ type Config struct {
 	TLS     tls     `mapstructure:",squash"`
 	Request request `mapstructure:",squash"`
 	Editor  editor  `mapstructure:",squash"`
+	Format  format  `mapstructure:",squash"`
+	Cache   cache   `mapstructure:",squash"`
 }
 
 // proto represents a set of proto files related configuration.
 type proto struct {
-	ImportPaths []string `mapstructure:"import_paths"`
-	ProtoFiles  []string `mapstructure:"proto_files"`
+	ImportPaths  []string `mapstructure:"import_paths"`
+	ProtoFiles   []string `mapstructure:"proto_files"`
+	AutoDiscover bool     `mapstructure:"auto_discover"`
+	Protosets    []string `mapstructure:"protosets"`
+	Layered      string   `mapstructure:"layered"`
 }
 
 // server represents a configuration of a remote server connection.
 type server struct {
-	Address    string `mapstructure:"address"`
-	Reflection bool   `mapstructure:"reflection"`
-	Web        bool   `mapstructure:"web"`
+	Address           string        `mapstructure:"address"`
+	Reflection        bool          `mapstructure:"reflection"`
+	ReflectionAddress string        `mapstructure:"reflection_address"`
+	Web               bool          `mapstructure:"web"`
+	ConnectTimeout    time.Duration `mapstructure:"connect_timeout"`
 }
 
 type tls struct {
@@ -34,9 +43,26 @@ type request struct {
 	Metadata map[string]string `mapstructure:"metadata"`
 	Package  string            `mapstructure:"package"`
 	Service  string            `mapstructure:"service"`
+	Timeout  time.Duration     `mapstructure:"timeout"`
 }
 
 // editor represents a message editor utility configuration.
 type editor struct {
 	Cmd string `mapstructure:"editor"`
 }
+
+// format represents a configuration of request and response message formats.
+type format struct {
+	Input           string `mapstructure:"input_format"`
+	Output          string `mapstructure:"format"`
+	UseProtoNames   bool   `mapstructure:"use_proto_names"`
+	UseEnumNumbers  bool   `mapstructure:"use_enum_numbers"`
+	EmitUnpopulated bool   `mapstructure:"emit_unpopulated"`
+	DiscardUnknown  bool   `mapstructure:"discard_unknown"`
+	AllowPartial    bool   `mapstructure:"allow_partial"`
+}
+
+// cache represents a configuration of the descriptor cache.
+type cache struct {
+	TTL time.Duration `mapstructure:"cache_ttl"`
+}
@@ -15,7 +15,7 @@ import (
 // RegisterDataFlag registers the data flag with the provided command.
 // The flag allows the user to specify request data.
 func RegisterDataFlag(cmd *cobra.Command) {
-	cmd.Flags().StringP("data", "d", "", "request data in json format")
+	cmd.Flags().StringP("data", "d", "", "request data in the --input-format format")
 }
 
 // HandleDataFlag returns an io.ReadCloser for the data specified in the flag.
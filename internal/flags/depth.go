@@ -0,0 +1,26 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultDepth is a default nesting limit of populated messages in request skeletons.
+const defaultDepth = 5
+
+// RegisterDepthFlag registers the depth flag with the provided command.
+// The flag limits the nesting of messages populated in a request skeleton.
+func RegisterDepthFlag(cmd *cobra.Command) {
+	cmd.Flags().Int("depth", defaultDepth, "nesting limit of populated messages, deeper messages are left empty")
+}
+
+// HandleDepthFlag returns the nesting limit of populated messages.
+func HandleDepthFlag(cmd *cobra.Command) (int, error) {
+	depth, err := cmd.Flags().GetInt("depth")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get depth flag: %w", err)
+	}
+
+	return depth, nil
+}
@@ -11,9 +11,9 @@ import (
 )
 
 // RegisterEditFlag registers the edit flag for a given command.
-// The flag allows the user edit input with a text editor of choice.
+// The flag allows the user to edit a request with a text editor of choice.
 func RegisterEditFlag(cmd *cobra.Command) {
-	cmd.Flags().BoolP("edit", "e", false, "edit the request before printing")
+	cmd.Flags().BoolP("edit", "e", false, "edit the request in a text editor")
 }
 
 // HandleEditFlag returns an editor.Editor instance if the edit flag is set. Otherwise it returns nil.
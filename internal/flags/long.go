@@ -0,0 +1,23 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// RegisterLongFlag registers the long flag with the provided command.
+// The flag allows the user to see detailed information about the listed elements.
+func RegisterLongFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolP("long", "l", false, "use a long listing format with request and response types")
+}
+
+// HandleLongFlag returns true if the long listing format is requested.
+func HandleLongFlag(cmd *cobra.Command) (bool, error) {
+	long, err := cmd.Flags().GetBool("long")
+	if err != nil {
+		return false, fmt.Errorf("failed to get long flag: %w", err)
+	}
+
+	return long, nil
+}
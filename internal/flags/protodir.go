@@ -0,0 +1,24 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// RegisterProtoDirFlag registers the proto dir flag with the provided command.
+// The flag allows the user to export descriptors as .proto files instead of a protoset.
+func RegisterProtoDirFlag(cmd *cobra.Command) {
+	cmd.Flags().String("proto-dir", "", "directory to write .proto files to instead of a protoset")
+	cmd.MarkFlagDirname("proto-dir")
+}
+
+// HandleProtoDirFlag returns the directory to write .proto files to, or an empty string if it's not set.
+func HandleProtoDirFlag(cmd *cobra.Command) (string, error) {
+	dir, err := cmd.Flags().GetString("proto-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to get proto dir flag: %w", err)
+	}
+
+	return dir, nil
+}
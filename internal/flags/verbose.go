@@ -0,0 +1,29 @@
+package flags
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// RegisterVerboseFlag registers the verbose flag with the provided command.
+// The flag allows the user to see response headers, trailers and the final status of a call.
+func RegisterVerboseFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolP("verbose", "v", false, "print response headers, trailers and status to stderr")
+}
+
+// HandleVerboseFlag returns a writer for verbose output.
+// If the verbose flag is set, the command's error output is returned, otherwise output is discarded.
+func HandleVerboseFlag(cmd *cobra.Command) (io.Writer, error) {
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get verbose flag: %w", err)
+	}
+
+	if !verbose {
+		return io.Discard, nil
+	}
+
+	return cmd.ErrOrStderr(), nil
+}
@@ -0,0 +1,205 @@
+package proto
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/heartandu/easyrpc/internal/config"
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+)
+
+const (
+	cacheDirPerm  = 0o755
+	cacheFilePerm = 0o600
+)
+
+// CacheDir returns the directory where descriptors fetched with server reflection are cached.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user cache dir: %w", err)
+	}
+
+	return filepath.Join(dir, "easyrpc", "descriptors"), nil
+}
+
+// ClearCache removes all cached descriptors.
+func ClearCache(fs afero.Fs) error {
+	dir, err := CacheDir()
+	if err != nil {
+		return err
+	}
+
+	if err := fs.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove cache dir: %w", err)
+	}
+
+	return nil
+}
+
+// cachedReflectionSource returns a source of descriptors cached for the server, if the cache is fresh.
+// Otherwise, it returns a server reflection source, which caches the descriptors once it lists methods.
+func cachedReflectionSource(
+	ctx context.Context,
+	fs afero.Fs,
+	cfg *config.Config,
+	clientConn grpc.ClientConnInterface,
+) (descriptor.Source, error) {
+	live, err := descriptor.ReflectionSource(ctx, clientConn)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // The error is wrapped by the caller.
+	}
+
+	path, err := cachePath(cfg)
+	if err != nil {
+		return live, nil //nolint:nilerr // The cache is best-effort, server reflection works without it.
+	}
+
+	if set, ok := loadCache(fs, path, cfg.Cache.TTL); ok {
+		if cached, err := descriptor.FileDescriptorSetSource(set); err == nil {
+			return &cachedSource{Source: cached, live: live}, nil
+		}
+	}
+
+	return &cachingSource{Source: live, fs: fs, path: path}, nil
+}
+
+// cachePath returns the path of the cache file of the server, which is unique for the address and TLS settings.
+func cachePath(cfg *config.Config) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	key := strings.Join([]string{
+		cfg.Server.Address,
+		strconv.FormatBool(cfg.Server.Web),
+		strconv.FormatBool(cfg.TLS.Enabled),
+		cfg.TLS.CACert,
+		cfg.TLS.Cert,
+		cfg.TLS.Key,
+	}, "\x00")
+
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".binpb"), nil
+}
+
+// loadCache reads the cached descriptors, unless they are older than the TTL.
+func loadCache(fs afero.Fs, path string, ttl time.Duration) (*descriptorpb.FileDescriptorSet, bool) {
+	info, err := fs.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > ttl {
+		return nil, false
+	}
+
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, false
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(b, set); err != nil {
+		return nil, false
+	}
+
+	return set, true
+}
+
+func storeCache(fs afero.Fs, path string, set *descriptorpb.FileDescriptorSet) error {
+	b, err := proto.Marshal(set)
+	if err != nil {
+		return fmt.Errorf("failed to marshal descriptors: %w", err)
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(path), cacheDirPerm); err != nil {
+		return fmt.Errorf("failed to create cache dir: %w", err)
+	}
+
+	if err := afero.WriteFile(fs, path, b, cacheFilePerm); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	return nil
+}
+
+// cachingSource is a server reflection source, which fills the cache lazily. Listing methods fetches files
+// of all services, so the descriptors are cached then, without querying the whole schema for other commands.
+type cachingSource struct {
+	descriptor.Source
+	fs   afero.Fs
+	path string
+}
+
+// ListMethods lists methods with server reflection and caches the fetched descriptors.
+func (s *cachingSource) ListMethods() ([]string, error) {
+	methods, err := s.Source.ListMethods()
+	if err != nil {
+		return nil, err //nolint:wrapcheck // This is a simple decorator.
+	}
+
+	// Files of the services are already fetched, so only the list of services is queried again.
+	if set, err := descriptor.NewFileDescriptorSet(s.Source); err == nil {
+		storeCache(s.fs, s.path, set) //nolint:errcheck,gosec // The cache is best-effort, server reflection works without it.
+	}
+
+	return methods, nil
+}
+
+// cachedSource is a source of cached descriptors, which falls back to server reflection
+// for symbols missing from the cache, e.g. added to the server after caching or unrelated to any service.
+type cachedSource struct {
+	descriptor.Source
+	live descriptor.Source
+}
+
+// FindSymbol finds a symbol in the cache or with server reflection.
+func (s *cachedSource) FindSymbol(name string) (protoreflect.Descriptor, error) {
+	if d, err := s.Source.FindSymbol(name); err == nil {
+		return d, nil
+	}
+
+	return s.live.FindSymbol(name) //nolint:wrapcheck // This is a simple decorator.
+}
+
+// FindMethod finds a method in the cache or with server reflection.
+func (s *cachedSource) FindMethod(method string) (descriptor.Method, error) {
+	if m, err := s.Source.FindMethod(method); err == nil {
+		return m, nil
+	}
+
+	return s.live.FindMethod(method) //nolint:wrapcheck // This is a simple decorator.
+}
+
+// FindMessage finds a message in the cache or with server reflection.
+func (s *cachedSource) FindMessage(name string) (protoreflect.MessageDescriptor, error) {
+	if md, err := s.Source.FindMessage(name); err == nil {
+		return md, nil
+	}
+
+	return s.live.FindMessage(name) //nolint:wrapcheck // This is a simple decorator.
+}
+
+// FindExtension finds an extension in the cache or with server reflection.
+func (s *cachedSource) FindExtension(
+	message string,
+	number protoreflect.FieldNumber,
+) (protoreflect.ExtensionDescriptor, error) {
+	if xd, err := s.Source.FindExtension(message, number); err == nil {
+		return xd, nil
+	}
+
+	return s.live.FindExtension(message, number) //nolint:wrapcheck // This is a simple decorator.
+}
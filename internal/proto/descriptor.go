@@ -6,12 +6,27 @@ import (
 
 	"github.com/spf13/afero"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
+	"github.com/heartandu/easyrpc/internal/client"
 	"github.com/heartandu/easyrpc/internal/config"
 	"github.com/heartandu/easyrpc/pkg/descriptor"
 )
 
+const (
+	// LayeredLocalFirst is an order of the layered source, where proto files or protosets
+	// are searched before server reflection.
+	LayeredLocalFirst = "local-first"
+	// LayeredReflectionFirst is an order of the layered source, where server reflection
+	// is searched before proto files or protosets.
+	LayeredReflectionFirst = "reflection-first"
+)
+
+// LayeredOrders is a list of supported orders of the layered source.
+var LayeredOrders = []string{LayeredLocalFirst, LayeredReflectionFirst}
+
 // NewDescriptorSource returns a new descriptor source based on the provided configuration.
+// If the layered source order is configured, both server reflection and proto files or protosets are used.
 func NewDescriptorSource(
 	ctx context.Context,
 	fs afero.Fs,
@@ -23,10 +38,15 @@ func NewDescriptorSource(
 		err     error
 	)
 
-	if cfg.Server.Reflection {
-		descSrc, err = descriptor.ReflectionSource(ctx, clientConn)
-	} else {
-		descSrc, err = descriptor.ProtoFilesSource(ctx, fs, cfg.Proto.ImportPaths, cfg.Proto.ProtoFiles)
+	switch cfg.Proto.Layered {
+	case LayeredLocalFirst, LayeredReflectionFirst:
+		descSrc, err = layeredSource(ctx, fs, cfg, clientConn)
+	default:
+		if cfg.Server.Reflection {
+			descSrc, err = reflectionSource(ctx, fs, cfg, clientConn)
+		} else {
+			descSrc, err = localSource(ctx, fs, cfg)
+		}
 	}
 
 	if err != nil {
@@ -35,3 +55,72 @@ func NewDescriptorSource(
 
 	return descSrc, nil
 }
+
+func layeredSource(
+	ctx context.Context,
+	fs afero.Fs,
+	cfg *config.Config,
+	clientConn grpc.ClientConnInterface,
+) (descriptor.Source, error) {
+	local, err := localSource(ctx, fs, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	reflection, err := reflectionSource(ctx, fs, cfg, clientConn)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Proto.Layered == LayeredReflectionFirst {
+		return descriptor.LayeredSource(reflection, local), nil
+	}
+
+	return descriptor.LayeredSource(local, reflection), nil
+}
+
+// reflectionSource returns a server reflection source, which sends the configured metadata with its requests.
+// If the reflection address is configured, a separate connection to it is used.
+func reflectionSource(
+	ctx context.Context,
+	fs afero.Fs,
+	cfg *config.Config,
+	clientConn grpc.ClientConnInterface,
+) (descriptor.Source, error) {
+	ctx = metadata.NewOutgoingContext(ctx, metadata.New(cfg.Request.Metadata))
+
+	if cfg.Server.ReflectionAddress != "" {
+		reflectionCfg := *cfg
+		reflectionCfg.Server.Address = cfg.Server.ReflectionAddress
+		cfg = &reflectionCfg
+
+		var err error
+
+		clientConn, err = client.New(ctx, fs, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create reflection client connection: %w", err)
+		}
+	}
+
+	if cfg.Cache.TTL > 0 {
+		return cachedReflectionSource(ctx, fs, cfg, clientConn)
+	}
+
+	return descriptor.ReflectionSource(ctx, clientConn) //nolint:wrapcheck // The error is wrapped by the caller.
+}
+
+// localSource returns a source of protosets if they are configured, a source of all proto files
+// under the import paths if auto discovery is enabled, or a source of the configured proto files otherwise.
+func localSource(ctx context.Context, fs afero.Fs, cfg *config.Config) (descriptor.Source, error) {
+	if len(cfg.Proto.Protosets) > 0 {
+		return descriptor.ProtosetSource(fs, cfg.Proto.Protosets) //nolint:wrapcheck // The error is wrapped by the caller.
+	}
+
+	if cfg.Proto.AutoDiscover {
+		//nolint:wrapcheck // The error is wrapped by the caller.
+		return descriptor.DiscoveredProtoFilesSource(ctx, fs, cfg.Proto.ImportPaths)
+	}
+
+	//nolint:wrapcheck // The error is wrapped by the caller.
+	return descriptor.ProtoFilesSource(ctx, fs, cfg.Proto.ImportPaths, cfg.Proto.ProtoFiles)
+}
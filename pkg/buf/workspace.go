@@ -0,0 +1,338 @@
+package buf
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	workFile   = "buf.work.yaml"
+	configFile = "buf.yaml"
+	lockFile   = "buf.lock"
+
+	// version2 is the version of buf.yaml files, which define a whole workspace.
+	version2 = "v2"
+	// defaultRemote is a remote of dependencies, which don't specify it.
+	defaultRemote = "buf.build"
+)
+
+// ErrWorkspaceNotFound is returned when neither the directory nor its parents contain a buf workspace or module.
+var ErrWorkspaceNotFound = errors.New("buf workspace not found")
+
+// Workspace represents a buf workspace or a single buf module.
+type Workspace struct {
+	// Dir is the root directory of the workspace.
+	Dir string
+	// Modules are the modules of the workspace.
+	Modules []Module
+	// Deps are the directories of the workspace dependencies found in the buf cache.
+	Deps []string
+}
+
+// Module represents a buf module.
+type Module struct {
+	// Path is the root directory of the module.
+	Path string
+	// Excludes are the directories excluded from the module.
+	Excludes []string
+}
+
+// workConfig is the contents of a buf.work.yaml file.
+type workConfig struct {
+	Directories []string `yaml:"directories"`
+}
+
+// moduleConfig is the contents of a buf.yaml file of any version.
+type moduleConfig struct {
+	Version string `yaml:"version"`
+	Build   struct {
+		Excludes []string `yaml:"excludes"`
+	} `yaml:"build"`
+	Modules []struct {
+		Path     string   `yaml:"path"`
+		Excludes []string `yaml:"excludes"`
+	} `yaml:"modules"`
+}
+
+// lockConfig is the contents of a buf.lock file of any version.
+type lockConfig struct {
+	Deps []struct {
+		Remote     string `yaml:"remote"`
+		Owner      string `yaml:"owner"`
+		Repository string `yaml:"repository"`
+		Name       string `yaml:"name"`
+		Commit     string `yaml:"commit"`
+		Digest     string `yaml:"digest"`
+	} `yaml:"deps"`
+}
+
+// FindWorkspace looks for a buf workspace in the directory and its parents.
+// A buf.work.yaml file or a buf.yaml file of version v2 defines a workspace,
+// while a buf.yaml file of an older version defines a single module, unless it belongs to a workspace.
+// Dependencies from buf.lock files are resolved in the buf cache directory, missing ones are skipped.
+func FindWorkspace(fs afero.Fs, dir string) (*Workspace, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	var module *parsedWorkspace
+
+	for {
+		ws, err := readWorkspace(fs, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case ws == nil:
+			// Nothing is defined in the directory.
+		case ws.isModule:
+			// The closest module is remembered until it's clear, whether it belongs to a workspace.
+			if module == nil {
+				module = ws
+			}
+		case module == nil || ws.contains(module.Dir):
+			return ws.resolve(fs)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+
+		dir = parent
+	}
+
+	if module != nil {
+		return module.resolve(fs)
+	}
+
+	return nil, ErrWorkspaceNotFound
+}
+
+// ModulePaths returns the root directories of the workspace modules.
+func (w *Workspace) ModulePaths() []string {
+	paths := make([]string, 0, len(w.Modules))
+	for _, m := range w.Modules {
+		paths = append(paths, m.Path)
+	}
+
+	return paths
+}
+
+// Excluded reports whether the path is inside a directory excluded from one of the modules.
+func (w *Workspace) Excluded(path string) bool {
+	if len(w.Modules) == 0 {
+		return false
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	for _, m := range w.Modules {
+		for _, exclude := range m.Excludes {
+			if isWithin(exclude, path) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parsedWorkspace is a workspace read from the configuration files, which dependencies aren't resolved yet.
+type parsedWorkspace struct {
+	Workspace
+
+	// isModule is true if the workspace is a single module defined by a buf.yaml file of an older version.
+	isModule bool
+	// isV2 is true if the workspace is defined by a buf.yaml file of version v2.
+	isV2 bool
+}
+
+// readWorkspace reads a workspace or a module defined in the directory. It returns nil if there is none.
+func readWorkspace(fs afero.Fs, dir string) (*parsedWorkspace, error) {
+	var work workConfig
+
+	found, err := readYAML(fs, filepath.Join(dir, workFile), &work)
+	if err != nil {
+		return nil, err
+	}
+
+	if found {
+		ws := &parsedWorkspace{Workspace: Workspace{Dir: dir}}
+
+		for _, d := range work.Directories {
+			m := Module{Path: filepath.Join(dir, d)}
+
+			var cfg moduleConfig
+			if _, err := readYAML(fs, filepath.Join(m.Path, configFile), &cfg); err != nil {
+				return nil, err
+			}
+
+			m.Excludes = joinAll(m.Path, cfg.Build.Excludes)
+			ws.Modules = append(ws.Modules, m)
+		}
+
+		return ws, nil
+	}
+
+	var cfg moduleConfig
+
+	found, err = readYAML(fs, filepath.Join(dir, configFile), &cfg)
+	if err != nil || !found {
+		return nil, err //nolint:nilnil // A directory without a workspace isn't an error.
+	}
+
+	if cfg.Version != version2 {
+		return &parsedWorkspace{
+			Workspace: Workspace{
+				Dir:     dir,
+				Modules: []Module{{Path: dir, Excludes: joinAll(dir, cfg.Build.Excludes)}},
+			},
+			isModule: true,
+		}, nil
+	}
+
+	ws := &parsedWorkspace{Workspace: Workspace{Dir: dir}, isV2: true}
+
+	for _, m := range cfg.Modules {
+		ws.Modules = append(ws.Modules, Module{
+			Path:     filepath.Join(dir, m.Path),
+			Excludes: joinAll(dir, m.Excludes),
+		})
+	}
+
+	if len(ws.Modules) == 0 {
+		ws.Modules = []Module{{Path: dir}}
+	}
+
+	return ws, nil
+}
+
+// contains reports whether the directory is one of the workspace modules.
+func (w *parsedWorkspace) contains(dir string) bool {
+	return slices.Contains(w.ModulePaths(), dir)
+}
+
+// resolve looks up the dependencies of the workspace in the buf cache.
+// Lock files are placed in the workspace root for v2 workspaces, and in every module root otherwise.
+func (w *parsedWorkspace) resolve(fs afero.Fs) (*Workspace, error) {
+	lockDirs := []string{w.Dir}
+	if !w.isV2 {
+		lockDirs = w.ModulePaths()
+	}
+
+	bufCache, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	ws := w.Workspace
+
+	for _, dir := range lockDirs {
+		var lock lockConfig
+		if _, err := readYAML(fs, filepath.Join(dir, lockFile), &lock); err != nil {
+			return nil, err
+		}
+
+		for _, dep := range lock.Deps {
+			name := dep.Name
+			if name == "" {
+				remote := cmp.Or(dep.Remote, defaultRemote)
+				name = strings.Join([]string{remote, dep.Owner, dep.Repository}, "/")
+			}
+
+			depDir, ok := findDep(fs, bufCache, name, dep.Commit, dep.Digest)
+			if ok && !slices.Contains(ws.Deps, depDir) {
+				ws.Deps = append(ws.Deps, depDir)
+			}
+		}
+	}
+
+	return &ws, nil
+}
+
+// findDep returns a directory of the dependency files in the buf cache.
+// Both the current and the legacy cache layouts are checked.
+func findDep(fs afero.Fs, cacheDir, name, commit, digest string) (string, bool) {
+	digestType, _, _ := strings.Cut(digest, ":")
+	name = filepath.FromSlash(name)
+
+	candidates := []string{
+		filepath.Join(cacheDir, "v3", "modules", digestType, name, commit, "files"),
+		filepath.Join(cacheDir, "v1", "module", "data", name, commit),
+	}
+
+	for _, dir := range candidates {
+		if ok, err := afero.DirExists(fs, dir); err == nil && ok {
+			return dir, true
+		}
+	}
+
+	return "", false
+}
+
+// cacheDir returns the buf cache directory, following the same rules as buf itself.
+func cacheDir() (string, error) {
+	if dir := os.Getenv("BUF_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "buf"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home dir: %w", err)
+	}
+
+	return filepath.Join(home, ".cache", "buf"), nil
+}
+
+// readYAML decodes the file into v. It returns false if the file doesn't exist.
+func readYAML(fs afero.Fs, path string, v any) (bool, error) {
+	b, err := afero.ReadFile(fs, path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(b, v); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return true, nil
+}
+
+// joinAll joins every path with the directory.
+func joinAll(dir string, paths []string) []string {
+	result := make([]string, 0, len(paths))
+	for _, p := range paths {
+		result = append(result, filepath.Join(dir, p))
+	}
+
+	return result
+}
+
+// isWithin reports whether the path is the directory itself or is inside of it.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
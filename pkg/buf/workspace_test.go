@@ -0,0 +1,145 @@
+package buf_test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heartandu/easyrpc/pkg/buf"
+)
+
+func TestFindWorkspace(t *testing.T) {
+	t.Setenv("BUF_CACHE_DIR", "/cache")
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		dir     string
+		want    *buf.Workspace
+		wantErr error
+	}{
+		{
+			name: "v1 workspace",
+			files: map[string]string{
+				"/repo/buf.work.yaml":  "version: v1\ndirectories:\n  - proto\n  - vendor\n",
+				"/repo/proto/buf.yaml": "version: v1\nbuild:\n  excludes:\n    - internal\n",
+				"/repo/proto/buf.lock": `version: v1
+deps:
+  - remote: buf.build
+    owner: googleapis
+    repository: googleapis
+    commit: abc
+    digest: shake256:123
+  - remote: buf.build
+    owner: missing
+    repository: missing
+    commit: abc
+`,
+				"/cache/v1/module/data/buf.build/googleapis/googleapis/abc/google/api/http.proto": "",
+			},
+			dir: "/repo/proto/foo",
+			want: &buf.Workspace{
+				Dir: "/repo",
+				Modules: []buf.Module{
+					{Path: "/repo/proto", Excludes: []string{"/repo/proto/internal"}},
+					{Path: "/repo/vendor", Excludes: []string{}},
+				},
+				Deps: []string{"/cache/v1/module/data/buf.build/googleapis/googleapis/abc"},
+			},
+		},
+		{
+			name: "v2 workspace",
+			files: map[string]string{
+				"/repo/buf.yaml": `version: v2
+modules:
+  - path: proto
+    excludes:
+      - proto/legacy
+`,
+				"/repo/buf.lock": `version: v2
+deps:
+  - name: buf.build/bufbuild/protovalidate
+    commit: def
+    digest: b5:456
+`,
+				"/cache/v3/modules/b5/buf.build/bufbuild/protovalidate/def/files/buf/validate/validate.proto": "",
+			},
+			dir: "/repo/proto",
+			want: &buf.Workspace{
+				Dir:     "/repo",
+				Modules: []buf.Module{{Path: "/repo/proto", Excludes: []string{"/repo/proto/legacy"}}},
+				Deps:    []string{"/cache/v3/modules/b5/buf.build/bufbuild/protovalidate/def/files"},
+			},
+		},
+		{
+			name: "v2 workspace without modules",
+			files: map[string]string{
+				"/repo/buf.yaml": "version: v2\n",
+			},
+			dir: "/repo",
+			want: &buf.Workspace{
+				Dir:     "/repo",
+				Modules: []buf.Module{{Path: "/repo"}},
+			},
+		},
+		{
+			name: "single module",
+			files: map[string]string{
+				"/mod/buf.yaml":  "version: v1\n",
+				"/mod/a/b.proto": "",
+			},
+			dir: "/mod/a",
+			want: &buf.Workspace{
+				Dir:     "/mod",
+				Modules: []buf.Module{{Path: "/mod", Excludes: []string{}}},
+			},
+		},
+		{
+			name: "module outside of workspace",
+			files: map[string]string{
+				"/repo/buf.work.yaml":  "version: v1\ndirectories:\n  - proto\n",
+				"/repo/other/buf.yaml": "version: v1\n",
+			},
+			dir: "/repo/other",
+			want: &buf.Workspace{
+				Dir:     "/repo/other",
+				Modules: []buf.Module{{Path: "/repo/other", Excludes: []string{}}},
+			},
+		},
+		{
+			name: "not found",
+			files: map[string]string{
+				"/repo/a.proto": "",
+			},
+			dir:     "/repo",
+			wantErr: buf.ErrWorkspaceNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for name, contents := range tt.files {
+				require.NoError(t, afero.WriteFile(fs, name, []byte(contents), 0o644))
+			}
+
+			got, err := buf.FindWorkspace(fs, tt.dir)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWorkspaceExcluded(t *testing.T) {
+	t.Parallel()
+
+	ws := &buf.Workspace{
+		Modules: []buf.Module{{Path: "/repo/proto", Excludes: []string{"/repo/proto/internal"}}},
+	}
+
+	require.True(t, ws.Excluded("/repo/proto/internal"))
+	require.True(t, ws.Excluded("/repo/proto/internal/a.proto"))
+	require.False(t, ws.Excluded("/repo/proto/internals/a.proto"))
+	require.False(t, ws.Excluded("/repo/proto/a.proto"))
+	require.False(t, (&buf.Workspace{}).Excluded("/repo/proto/internal/a.proto"))
+}
@@ -19,8 +19,9 @@ func NewWebClient(cc *grpcweb.ClientConn) *WebClient {
 }
 
 // Invoke makes a unary gRPC call to the server.
-func (c *WebClient) Invoke(ctx context.Context, method string, args, reply any, _ ...grpc.CallOption) error {
-	if err := c.cc.Invoke(ctx, method, args, reply); err != nil {
+// Header and trailer call options are translated to their gRPC-Web counterparts, other options are ignored.
+func (c *WebClient) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	if err := c.cc.Invoke(ctx, method, args, reply, webCallOptions(opts)...); err != nil {
 		return fmt.Errorf("failed to call wrapped invoke: %w", err)
 	}
 
@@ -41,3 +42,19 @@ func (c *WebClient) NewStream(
 
 	return stream, nil
 }
+
+// webCallOptions converts supported gRPC call options to gRPC-Web call options.
+func webCallOptions(opts []grpc.CallOption) []grpcweb.CallOption {
+	webOpts := make([]grpcweb.CallOption, 0, len(opts))
+
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			webOpts = append(webOpts, grpcweb.Header(o.HeaderAddr))
+		case grpc.TrailerCallOption:
+			webOpts = append(webOpts, grpcweb.Trailer(o.TrailerAddr))
+		}
+	}
+
+	return webOpts
+}
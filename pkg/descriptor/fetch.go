@@ -0,0 +1,286 @@
+package descriptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/jhump/protoreflect/desc"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	refv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	refv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reflectionStream is a server reflection stream of any version of the reflection API.
+type reflectionStream interface {
+	Send(req *refv1.ServerReflectionRequest) error
+	Recv() (*refv1.ServerReflectionResponse, error)
+	CloseSend() error
+}
+
+// openReflectionStream opens a server reflection stream.
+type openReflectionStream func(ctx context.Context, cc grpc.ClientConnInterface) (reflectionStream, error)
+
+// fetchServiceFiles fetches files of the services, which haven't been fetched yet.
+// Requests are pipelined over a single reflection stream, so the latency of the requests overlaps,
+// while the server sends every imported file only once per stream.
+// Services defined in already received files are skipped.
+func (s *serverReflectionSource) fetchServiceFiles(services []string) error {
+	pending := make([]string, 0, len(services))
+
+	for _, service := range services {
+		if s.serviceFile(service) == nil {
+			pending = append(pending, service)
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err := s.fetchServiceFilesWith(pending, openReflectionStreamV1)
+	if errors.Is(err, ErrReflectionNotSupported) {
+		err = s.fetchServiceFilesWith(pending, openReflectionStreamV1Alpha)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return s.buildServiceFiles()
+}
+
+// maxReflectionRequestsInFlight limits the number of requests pipelined over a reflection stream,
+// which are waiting for responses.
+const maxReflectionRequestsInFlight = 8
+
+// fetchServiceFilesWith fetches files of the services with a reflection stream opened by open.
+func (s *serverReflectionSource) fetchServiceFilesWith(services []string, open openReflectionStream) error {
+	g, ctx := errgroup.WithContext(s.ctx)
+
+	stream, err := open(ctx, s.cc)
+	if err != nil {
+		return reflectWrapErr("failed to open reflection stream", err)
+	}
+
+	inFlight := make(chan struct{}, maxReflectionRequestsInFlight)
+	sent := make(chan string, len(services))
+
+	g.Go(func() error {
+		defer close(sent)
+
+		for _, service := range services {
+			select {
+			case inFlight <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err() //nolint:wrapcheck // The receiver has already failed.
+			}
+
+			// The file may have been received for another service it defines.
+			if s.isServiceFetched(service) {
+				<-inFlight
+
+				continue
+			}
+
+			err := stream.Send(&refv1.ServerReflectionRequest{
+				MessageRequest: &refv1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+			})
+			if errors.Is(err, io.EOF) {
+				// The stream is broken, and its status is reported by the receiver.
+				return nil
+			}
+
+			if err != nil {
+				return reflectWrapErr("failed to query file containing symbol", err)
+			}
+
+			sent <- service
+		}
+
+		return stream.CloseSend() //nolint:wrapcheck // Closing the send direction doesn't fail in practice.
+	})
+
+	g.Go(func() error {
+		for range sent {
+			resp, err := stream.Recv()
+			if err != nil {
+				// The slot isn't released, so the sender stops on the canceled context.
+				return reflectWrapErr("failed to query file containing symbol", err)
+			}
+
+			<-inFlight
+
+			if err := s.addFetchedProtos(resp); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return g.Wait() //nolint:wrapcheck // Errors of the sender and the receiver are already wrapped.
+}
+
+// addFetchedProtos records the files of the reflection response. Files received before are kept as is.
+func (s *serverReflectionSource) addFetchedProtos(resp *refv1.ServerReflectionResponse) error {
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		err := status.Error(codes.Code(errResp.GetErrorCode()), errResp.GetErrorMessage())
+
+		return reflectWrapErr("failed to query file containing symbol", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fdp := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(b, fdp); err != nil {
+			return fmt.Errorf("failed to unmarshal file descriptor: %w", err)
+		}
+
+		if _, ok := s.fetchedProtos[fdp.GetName()]; ok {
+			continue
+		}
+
+		s.fetchedProtos[fdp.GetName()] = fdp
+
+		for _, sdp := range fdp.GetService() {
+			s.fetchedServices[qualifiedName(fdp.GetPackage(), sdp.GetName())] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+func (s *serverReflectionSource) isServiceFetched(service string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.fetchedServices[service]
+
+	return ok
+}
+
+// buildServiceFiles builds descriptors of all fetched files at once, and records the files defining services.
+func (s *serverReflectionSource) buildServiceFiles() error {
+	s.mu.RLock()
+	set := &descriptorpb.FileDescriptorSet{File: slices.Collect(maps.Values(s.fetchedProtos))}
+	s.mu.RUnlock()
+
+	seen := make(map[string]struct{}, len(set.GetFile()))
+	for _, fdp := range set.GetFile() {
+		seen[fdp.GetName()] = struct{}{}
+	}
+
+	// Servers may omit well-known types, which are known to the client anyway.
+	appendWellKnownImports(set, seen)
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return fmt.Errorf("failed to build fetched files: %w", err)
+	}
+
+	for _, fdp := range set.GetFile() {
+		if len(fdp.GetService()) == 0 {
+			continue
+		}
+
+		fd, err := files.FindFileByPath(fdp.GetName())
+		if err != nil {
+			return fmt.Errorf("failed to build fetched files: %w", err)
+		}
+
+		wrapped, err := desc.WrapFile(fd)
+		if err != nil {
+			return fmt.Errorf("failed to wrap file descriptor: %w", err)
+		}
+
+		s.addServiceFile(wrapped)
+	}
+
+	return nil
+}
+
+// openReflectionStreamV1 opens a stream of the v1 reflection API.
+func openReflectionStreamV1(ctx context.Context, cc grpc.ClientConnInterface) (reflectionStream, error) {
+	return refv1.NewServerReflectionClient(cc).ServerReflectionInfo(ctx) //nolint:wrapcheck // The caller wraps it.
+}
+
+// openReflectionStreamV1Alpha opens a stream of the v1alpha reflection API, which is used by older servers.
+func openReflectionStreamV1Alpha(ctx context.Context, cc grpc.ClientConnInterface) (reflectionStream, error) {
+	stream, err := refv1alpha.NewServerReflectionClient(cc).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // The caller wraps it.
+	}
+
+	return &v1AlphaStream{stream: stream}, nil
+}
+
+// v1AlphaStream adapts a v1alpha reflection stream to v1 messages.
+// Messages of both versions are identical, so they are converted through the wire format.
+type v1AlphaStream struct {
+	stream refv1alpha.ServerReflection_ServerReflectionInfoClient
+}
+
+// Send sends the request converted to v1alpha.
+func (s *v1AlphaStream) Send(req *refv1.ServerReflectionRequest) error {
+	alphaReq := &refv1alpha.ServerReflectionRequest{}
+	if err := convertMessage(req, alphaReq); err != nil {
+		return err
+	}
+
+	return s.stream.Send(alphaReq) //nolint:wrapcheck // This is a simple adapter.
+}
+
+// Recv receives a response converted to v1.
+func (s *v1AlphaStream) Recv() (*refv1.ServerReflectionResponse, error) {
+	alphaResp, err := s.stream.Recv()
+	if err != nil {
+		return nil, err //nolint:wrapcheck // This is a simple adapter.
+	}
+
+	resp := &refv1.ServerReflectionResponse{}
+	if err := convertMessage(alphaResp, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// CloseSend closes the send direction of the stream.
+func (s *v1AlphaStream) CloseSend() error {
+	return s.stream.CloseSend() //nolint:wrapcheck // This is a simple adapter.
+}
+
+// convertMessage converts a message to another one with the same wire format.
+func convertMessage(from, to proto.Message) error {
+	b, err := proto.Marshal(from)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reflection message: %w", err)
+	}
+
+	if err := proto.Unmarshal(b, to); err != nil {
+		return fmt.Errorf("failed to unmarshal reflection message: %w", err)
+	}
+
+	return nil
+}
+
+// qualifiedName returns the fully qualified name of a top-level symbol of the package.
+func qualifiedName(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+
+	return pkg + "." + name
+}
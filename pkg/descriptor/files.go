@@ -0,0 +1,71 @@
+package descriptor
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// NewFileDescriptorSet returns a FileDescriptorSet of the files listed by ListFiles.
+func NewFileDescriptorSet(src Source) (*descriptorpb.FileDescriptorSet, error) {
+	files, err := ListFiles(src)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{
+		File: make([]*descriptorpb.FileDescriptorProto, 0, len(files)),
+	}
+
+	for _, fd := range files {
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+
+	return set, nil
+}
+
+// ListFiles returns the files that define the services of the source, along with all files they import.
+// Imported files precede the files importing them.
+func ListFiles(src Source) ([]protoreflect.FileDescriptor, error) {
+	services, err := src.ListServices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	visited := make(map[string]struct{})
+	result := make([]protoreflect.FileDescriptor, 0)
+
+	for _, service := range services {
+		d, err := src.FindSymbol(service)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find service %q: %w", service, err)
+		}
+
+		if fd := d.ParentFile(); fd != nil {
+			result = appendFileWithImports(result, fd, visited)
+		}
+	}
+
+	return result, nil
+}
+
+func appendFileWithImports(
+	result []protoreflect.FileDescriptor,
+	fd protoreflect.FileDescriptor,
+	visited map[string]struct{},
+) []protoreflect.FileDescriptor {
+	if _, ok := visited[fd.Path()]; ok {
+		return result
+	}
+
+	visited[fd.Path()] = struct{}{}
+
+	imports := fd.Imports()
+	for i := range imports.Len() {
+		result = appendFileWithImports(result, imports.Get(i).FileDescriptor, visited)
+	}
+
+	return append(result, fd)
+}
@@ -0,0 +1,176 @@
+package descriptor
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/spf13/afero"
+
+	"github.com/heartandu/easyrpc/pkg/buf"
+	fsutil "github.com/heartandu/easyrpc/pkg/fs"
+)
+
+// importPath is a directory, which proto files are resolved from.
+// It belongs either to the local filesystem or to a mounted archive.
+type importPath struct {
+	fs afero.Fs
+	// dir is empty if the files are resolved relative to the working directory.
+	dir string
+	// excluded reports whether a local path is excluded from a buf module. It's nil unless it's a module root.
+	excluded func(path string) bool
+}
+
+// protoPaths are the import paths, which proto files are compiled from.
+type protoPaths struct {
+	// wd resolves files relative to the working directory before the roots, when the roots are buf module roots.
+	// It isn't searched for the files matching glob patterns, since they would be found twice.
+	wd []importPath
+	// roots are searched for the files matching glob patterns.
+	roots []importPath
+	// deps are only used to resolve imports.
+	deps []importPath
+}
+
+// resolveProtoPaths mounts the archives among the import paths. If there are no import paths,
+// files are resolved relative to the working directory, followed by the module roots and the cached dependencies
+// of the buf workspace containing the working directory. The files excluded from the modules are ignored
+// by the module roots.
+func resolveProtoPaths(fs afero.Fs, importPaths []string) (*protoPaths, error) {
+	if len(importPaths) > 0 {
+		roots, err := mountImportPaths(fs, importPaths, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return &protoPaths{roots: roots}, nil
+	}
+
+	ws := bufWorkspace(fs)
+	if len(ws.Modules) == 0 {
+		return &protoPaths{roots: []importPath{{fs: fs}}}, nil
+	}
+
+	roots, err := mountImportPaths(fs, ws.ModulePaths(), ws.Excluded)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := make([]importPath, 0, len(ws.Deps))
+	for _, dep := range ws.Deps {
+		deps = append(deps, importPath{fs: fs, dir: dep})
+	}
+
+	return &protoPaths{
+		// Paths relative to the working directory keep working inside of a workspace.
+		wd:    []importPath{{fs: fs}},
+		roots: roots,
+		deps:  deps,
+	}, nil
+}
+
+// bufWorkspace returns the buf workspace containing the working directory.
+// An empty workspace is returned if there is none or it can't be read, so it adds no import paths.
+// The buf configuration of the parent directories may belong to unrelated projects, so it must not fail compilation.
+func bufWorkspace(fs afero.Fs) *buf.Workspace {
+	wd, err := os.Getwd()
+	if err != nil {
+		return &buf.Workspace{}
+	}
+
+	ws, err := buf.FindWorkspace(fs, wd)
+	if err != nil {
+		return &buf.Workspace{}
+	}
+
+	return ws
+}
+
+// mountImportPaths returns the import paths with zip and gzipped tar archives mounted as read-only filesystems.
+// An import path may point at a directory inside of an archive, e.g. "schema.zip/proto".
+// If there are no import paths, the files are resolved relative to the working directory.
+// Excluded reports whether a local path is ignored, it may be nil.
+func mountImportPaths(fs afero.Fs, paths []string, excluded func(path string) bool) ([]importPath, error) {
+	if len(paths) == 0 {
+		return []importPath{{fs: fs, excluded: excluded}}, nil
+	}
+
+	result := make([]importPath, 0, len(paths))
+	mounted := make(map[string]afero.Fs)
+
+	for _, p := range paths {
+		archive, dir, ok := fsutil.SplitArchivePath(p)
+		if !ok {
+			result = append(result, importPath{fs: fs, dir: p, excluded: excluded})
+			continue
+		}
+
+		archiveFs, ok := mounted[archive]
+		if !ok {
+			name, err := fsutil.ExpandHome(archive)
+			if err != nil {
+				return nil, fmt.Errorf("failed to expand home: %w", err)
+			}
+
+			archiveFs, err = fsutil.OpenArchive(fs, name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to mount %q: %w", archive, err)
+			}
+
+			mounted[archive] = archiveFs
+		}
+
+		result = append(result, importPath{fs: archiveFs, dir: dir})
+	}
+
+	return result, nil
+}
+
+// resolver returns a resolver of the proto files under the import path.
+func (p importPath) resolver() protocompile.Resolver {
+	r := &protocompile.SourceResolver{Accessor: p.open}
+	if p.dir != "" {
+		r.ImportPaths = []string{p.dir}
+	}
+
+	return r
+}
+
+// open opens a file in the filesystem of the import path.
+func (p importPath) open(name string) (io.ReadCloser, error) {
+	name, err := fsutil.ExpandHome(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand home: %w", err)
+	}
+
+	if p.isExcluded(name) {
+		return nil, &iofs.PathError{Op: "open", Path: name, Err: iofs.ErrNotExist}
+	}
+
+	return p.fs.Open(name) //nolint:wrapcheck // The error is wrapped by the compiler.
+}
+
+// glob returns slash-separated paths of the files under the import path matching the pattern, relative to it.
+// Excluded files are skipped.
+func (p importPath) glob(pattern string) ([]string, error) {
+	dir := cmp.Or(p.dir, ".")
+
+	matches, err := fsutil.Glob(p.fs, dir, pattern)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // The error is wrapped by the caller.
+	}
+
+	return slices.DeleteFunc(matches, func(match string) bool {
+		return p.isExcluded(filepath.Join(dir, match))
+	}), nil
+}
+
+// isExcluded reports whether the path is excluded from a buf module.
+func (p importPath) isExcluded(name string) bool {
+	return p.excluded != nil && p.excluded(name)
+}
@@ -0,0 +1,114 @@
+package descriptor
+
+import (
+	"errors"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// layeredSource is a composite source, which looks up descriptors in the underlying sources in order.
+type layeredSource struct {
+	sources []Source
+}
+
+// LayeredSource creates a source of protocol buffer descriptors, which looks up descriptors
+// in the provided sources in order and returns the first one found.
+// Services and methods of all sources are listed, without duplicates, skipping the sources failing to list them.
+func LayeredSource(sources ...Source) Source {
+	return &layeredSource{sources: sources}
+}
+
+// ListServices returns a list of services of all sources.
+func (s *layeredSource) ListServices() ([]string, error) {
+	return s.list(Source.ListServices)
+}
+
+// ListMethods returns a list of methods of all sources.
+func (s *layeredSource) ListMethods() ([]string, error) {
+	return s.list(Source.ListMethods)
+}
+
+// list lists names of all sources without duplicates. Sources failing to list names are skipped,
+// unless all of them fail.
+func (s *layeredSource) list(listFunc func(Source) ([]string, error)) ([]string, error) {
+	var errs []error
+
+	encountered := make(map[string]struct{})
+	result := make([]string, 0)
+
+	for _, src := range s.sources {
+		names, err := listFunc(src)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		for _, name := range names {
+			if _, ok := encountered[name]; !ok {
+				result = append(result, name)
+				encountered[name] = struct{}{}
+			}
+		}
+	}
+
+	if len(errs) > 0 && len(errs) == len(s.sources) {
+		return nil, errors.Join(errs...)
+	}
+
+	return result, nil
+}
+
+// FindSymbol finds a symbol in the first source that provides it.
+func (s *layeredSource) FindSymbol(name string) (protoreflect.Descriptor, error) {
+	return find(s.sources, func(src Source) (protoreflect.Descriptor, error) {
+		return src.FindSymbol(name)
+	})
+}
+
+// FindMethod finds a method in the first source that provides it.
+func (s *layeredSource) FindMethod(method string) (Method, error) {
+	return find(s.sources, func(src Source) (Method, error) {
+		return src.FindMethod(method)
+	})
+}
+
+// FindMessage finds a message in the first source that provides it.
+func (s *layeredSource) FindMessage(name string) (protoreflect.MessageDescriptor, error) {
+	return find(s.sources, func(src Source) (protoreflect.MessageDescriptor, error) {
+		return src.FindMessage(name)
+	})
+}
+
+// FindExtension finds an extension in the first source that provides it.
+func (s *layeredSource) FindExtension(
+	message string,
+	number protoreflect.FieldNumber,
+) (protoreflect.ExtensionDescriptor, error) {
+	return find(s.sources, func(src Source) (protoreflect.ExtensionDescriptor, error) {
+		return src.FindExtension(message, number)
+	})
+}
+
+// find returns the first result found in the sources, or errors of all sources if none of them provides it.
+func find[T any](sources []Source, findFunc func(Source) (T, error)) (T, error) {
+	var (
+		zero T
+		errs []error
+	)
+
+	for _, src := range sources {
+		result, err := findFunc(src)
+		if err == nil {
+			return result, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return zero, ErrSymbolNotFound
+	}
+
+	return zero, errors.Join(errs...)
+}
@@ -0,0 +1,129 @@
+package descriptor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+)
+
+func newProtoSource(t *testing.T, contents string) descriptor.Source {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "layer.proto", []byte(contents), 0o644))
+
+	src, err := descriptor.ProtoFilesSource(context.Background(), fs, nil, []string{"layer.proto"})
+	require.NoError(t, err)
+
+	return src
+}
+
+func TestLayeredSource(t *testing.T) {
+	t.Parallel()
+
+	first := newProtoSource(t, `syntax = "proto3";
+package layer;
+service First { rpc Shared(Message) returns (Message); }
+message Message { string first = 1; }
+`)
+	second := newProtoSource(t, `syntax = "proto3";
+package layer;
+service First { rpc Shared(Message) returns (Message); rpc New(Message) returns (Message); }
+service Second { rpc Only(Message) returns (Message); }
+message Message { string second = 1; }
+message Extra {}
+`)
+
+	src := descriptor.LayeredSource(first, second)
+
+	services, err := src.ListServices()
+	require.NoError(t, err)
+	require.Equal(t, []string{"layer.First", "layer.Second"}, services)
+
+	methods, err := src.ListMethods()
+	require.NoError(t, err)
+	require.Equal(t, []string{"layer.First.Shared", "layer.First.New", "layer.Second.Only"}, methods)
+
+	md, err := src.FindMessage("layer.Message")
+	require.NoError(t, err)
+	require.NotNil(t, md.Fields().ByName("first"), "the first source must take precedence")
+
+	_, err = src.FindMethod("layer.First.New")
+	require.NoError(t, err)
+
+	_, err = src.FindSymbol("layer.Extra")
+	require.NoError(t, err)
+
+	_, err = src.FindExtension("layer.Message", protoreflect.FieldNumber(100))
+	require.ErrorIs(t, err, descriptor.ErrSymbolNotFound)
+
+	_, err = src.FindSymbol("layer.Unknown")
+	require.ErrorIs(t, err, descriptor.ErrSymbolNotFound)
+}
+
+// failingSource is a source failing to list services and methods.
+type failingSource struct {
+	descriptor.Source
+	err error
+}
+
+func (s failingSource) ListServices() ([]string, error) {
+	return nil, s.err
+}
+
+func (s failingSource) ListMethods() ([]string, error) {
+	return nil, s.err
+}
+
+func TestLayeredSourceListFailures(t *testing.T) {
+	t.Parallel()
+
+	errList := errors.New("list failed")
+	healthy := newProtoSource(t, `syntax = "proto3";
+package layer;
+service Healthy { rpc Method(Message) returns (Message); }
+message Message {}
+`)
+
+	tests := []struct {
+		name        string
+		sources     []descriptor.Source
+		wantMethods []string
+		wantErr     error
+	}{
+		{
+			name:        "failed source skipped",
+			sources:     []descriptor.Source{failingSource{err: errList}, healthy},
+			wantMethods: []string{"layer.Healthy.Method"},
+		},
+		{
+			name:    "all sources failed",
+			sources: []descriptor.Source{failingSource{err: errList}, failingSource{err: errList}},
+			wantErr: errList,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			src := descriptor.LayeredSource(tt.sources...)
+
+			methods, err := src.ListMethods()
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.wantMethods, methods)
+
+			services, err := src.ListServices()
+			require.ErrorIs(t, err, tt.wantErr)
+
+			if tt.wantErr == nil {
+				require.Equal(t, []string{"layer.Healthy"}, services)
+			}
+		})
+	}
+}
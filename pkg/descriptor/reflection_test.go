@@ -0,0 +1,325 @@
+package descriptor_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+)
+
+const (
+	manyFiles           = 10
+	manyServicesPerFile = 3
+	manyMethods         = 2
+	// maxInFlight is the limit of reflection requests pipelined over a stream while listing methods.
+	maxInFlight = 8
+)
+
+// serviceInfo is a list of services advertised by the reflection server.
+type serviceInfo map[string]grpc.ServiceInfo
+
+func (s serviceInfo) GetServiceInfo() map[string]grpc.ServiceInfo {
+	return s
+}
+
+// manyServicesFiles returns files sharing a common import, where every file defines servicesPerFile services.
+func manyServicesFiles(t *testing.T, servicesPerFile int) (*descriptorpb.FileDescriptorSet, serviceInfo, []string) {
+	t.Helper()
+
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:        proto.String("many/common.proto"),
+			Package:     proto.String("many"),
+			Syntax:      proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Common")}},
+		}},
+	}
+	services := serviceInfo{}
+	methods := make([]string, 0)
+
+	for i := range manyFiles {
+		fdp := &descriptorpb.FileDescriptorProto{
+			Name:       proto.String(fmt.Sprintf("many/file%d.proto", i)),
+			Package:    proto.String("many"),
+			Syntax:     proto.String("proto3"),
+			Dependency: []string{"many/common.proto"},
+		}
+
+		for j := range servicesPerFile {
+			name := fmt.Sprintf("Service%d_%d", i, j)
+			sdp := &descriptorpb.ServiceDescriptorProto{Name: proto.String(name)}
+
+			for k := range manyMethods {
+				sdp.Method = append(sdp.Method, &descriptorpb.MethodDescriptorProto{
+					Name:       proto.String(fmt.Sprintf("Method%d", k)),
+					InputType:  proto.String(".many.Common"),
+					OutputType: proto.String(".many.Common"),
+				})
+				methods = append(methods, fmt.Sprintf("many.%s.Method%d", name, k))
+			}
+
+			fdp.Service = append(fdp.Service, sdp)
+			services["many."+name] = grpc.ServiceInfo{}
+		}
+
+		set.File = append(set.File, fdp)
+	}
+
+	return set, services, methods
+}
+
+// reflectionStats are statistics of the reflection requests.
+type reflectionStats struct {
+	mu sync.Mutex
+	// symbolRequests is the number of requested files containing symbols.
+	symbolRequests int
+	// sentFiles is the number of times every file has been sent by the server.
+	sentFiles map[string]int
+	// maxInFlight is the maximum number of requests of a single stream waiting for responses.
+	maxInFlight int
+}
+
+func (s *reflectionStats) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.symbolRequests
+}
+
+// newManyServicesSource starts a reflection server with many services and returns a source using it,
+// along with the statistics of the requests. Only the v1alpha reflection API is served if v1alpha is set.
+func newManyServicesSource(
+	t *testing.T,
+	servicesPerFile int,
+	v1alpha bool,
+) (descriptor.Source, *reflectionStats, []string) {
+	t.Helper()
+
+	set, services, methods := manyServicesFiles(t, servicesPerFile)
+
+	files, err := protodesc.NewFiles(set)
+	require.NoError(t, err)
+
+	stats := &reflectionStats{sentFiles: make(map[string]int)}
+
+	s := grpc.NewServer(grpc.StreamInterceptor(func(
+		srv any,
+		ss grpc.ServerStream,
+		_ *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &countingServerStream{ServerStream: ss, stats: stats})
+	}))
+
+	opts := reflection.ServerOptions{Services: services, DescriptorResolver: files}
+	if v1alpha {
+		reflectionv1alpha.RegisterServerReflectionServer(s, reflection.NewServer(opts))
+	} else {
+		reflectionv1.RegisterServerReflectionServer(s, reflection.NewServerV1(opts))
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	go s.Serve(lis) //nolint:errcheck // The server is stopped with the test.
+
+	t.Cleanup(s.Stop)
+
+	cc, err := grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStreamInterceptor(func(
+			ctx context.Context,
+			sd *grpc.StreamDesc,
+			cc *grpc.ClientConn,
+			method string,
+			streamer grpc.Streamer,
+			opts ...grpc.CallOption,
+		) (grpc.ClientStream, error) {
+			cs, err := streamer(ctx, sd, cc, method, opts...)
+
+			return &countingClientStream{ClientStream: cs, stats: stats}, err
+		}),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { cc.Close() })
+
+	src, err := descriptor.ReflectionSource(context.Background(), cc)
+	require.NoError(t, err)
+
+	return src, stats, methods
+}
+
+// countingServerStream counts requests of files containing symbols and files sent in responses.
+type countingServerStream struct {
+	grpc.ServerStream
+	stats *reflectionStats
+}
+
+func (s *countingServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+
+	req, ok := m.(interface{ GetFileContainingSymbol() string })
+	if ok && err == nil && req.GetFileContainingSymbol() != "" {
+		s.stats.mu.Lock()
+		s.stats.symbolRequests++
+		s.stats.mu.Unlock()
+	}
+
+	return err
+}
+
+func (s *countingServerStream) SendMsg(m any) error {
+	// Responses of both reflection API versions have the same wire format.
+	if msg, ok := m.(proto.Message); ok {
+		resp := &reflectionv1.ServerReflectionResponse{}
+
+		if b, err := proto.Marshal(msg); err == nil && proto.Unmarshal(b, resp) == nil {
+			s.stats.mu.Lock()
+
+			for _, fdb := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+				fdp := &descriptorpb.FileDescriptorProto{}
+				if proto.Unmarshal(fdb, fdp) == nil {
+					s.stats.sentFiles[fdp.GetName()]++
+				}
+			}
+
+			s.stats.mu.Unlock()
+		}
+	}
+
+	return s.ServerStream.SendMsg(m)
+}
+
+// countingClientStream tracks the number of requests of the stream waiting for responses.
+type countingClientStream struct {
+	grpc.ClientStream
+	stats    *reflectionStats
+	inFlight int
+}
+
+func (s *countingClientStream) SendMsg(m any) error {
+	s.stats.mu.Lock()
+	s.inFlight++
+	s.stats.maxInFlight = max(s.stats.maxInFlight, s.inFlight)
+	s.stats.mu.Unlock()
+
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *countingClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	if err == nil {
+		s.stats.mu.Lock()
+		s.inFlight--
+		s.stats.mu.Unlock()
+	}
+
+	return err
+}
+
+func TestReflectionSourceListMethodsManyServices(t *testing.T) {
+	t.Parallel()
+
+	src, stats, methods := newManyServicesSource(t, manyServicesPerFile, false)
+
+	got, err := src.ListMethods()
+	require.NoError(t, err)
+	require.ElementsMatch(t, methods, got)
+
+	// Every file defines several services, so some of them are resolved with files fetched for others.
+	requests := stats.requests()
+	require.Less(t, requests, manyFiles*manyServicesPerFile)
+	require.LessOrEqual(t, stats.maxInFlight, maxInFlight)
+
+	// The common import is sent only once, while service files are sent once per request of their services.
+	require.Equal(t, 1, stats.sentFiles["many/common.proto"])
+
+	serviceFiles := 0
+
+	for i := range manyFiles {
+		sent := stats.sentFiles[fmt.Sprintf("many/file%d.proto", i)]
+		require.Positive(t, sent)
+
+		serviceFiles += sent
+	}
+
+	require.Equal(t, requests, serviceFiles)
+
+	got, err = src.ListMethods()
+	require.NoError(t, err)
+	require.ElementsMatch(t, methods, got)
+
+	d, err := src.FindSymbol("many.Service0_0.Method1")
+	require.NoError(t, err)
+	require.Equal(t, "many.Service0_0.Method1", string(d.FullName()))
+
+	require.Equal(t, requests, stats.requests(), "fetched files must be reused")
+}
+
+func TestReflectionSourceListMethodsFetchesFilesOnce(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		v1alpha bool
+	}{
+		{name: "v1"},
+		{name: "v1alpha", v1alpha: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			src, stats, methods := newManyServicesSource(t, 1, tt.v1alpha)
+
+			got, err := src.ListMethods()
+			require.NoError(t, err)
+			require.ElementsMatch(t, methods, got)
+
+			want := map[string]int{"many/common.proto": 1}
+			for i := range manyFiles {
+				want[fmt.Sprintf("many/file%d.proto", i)] = 1
+			}
+
+			require.Equal(t, want, stats.sentFiles)
+			require.Equal(t, manyFiles, stats.requests())
+			require.LessOrEqual(t, stats.maxInFlight, maxInFlight)
+		})
+	}
+}
+
+func TestReflectionSourceListMethodsOrder(t *testing.T) {
+	t.Parallel()
+
+	src, _, _ := newManyServicesSource(t, manyServicesPerFile, false)
+
+	services, err := src.ListServices()
+	require.NoError(t, err)
+
+	got, err := src.ListMethods()
+	require.NoError(t, err)
+	require.Len(t, got, len(services)*manyMethods)
+
+	for i, service := range services {
+		for k := range manyMethods {
+			require.Equal(t, fmt.Sprintf("%s.Method%d", service, k), got[i*manyMethods+k])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package descriptor
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Resolver is an interface for resolving message and extension types,
+// compatible with protojson and prototext resolvers.
+type Resolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// NewTypeResolver returns a Resolver that looks message and extension types up in the global registry first,
+// and falls back to the descriptor source.
+func NewTypeResolver(src Source) Resolver {
+	return &typeResolver{src: src}
+}
+
+type typeResolver struct {
+	src Source
+}
+
+// FindMessageByName looks up a message by its full name.
+func (r *typeResolver) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(name)
+	if err == nil || !errors.Is(err, protoregistry.NotFound) {
+		return mt, err //nolint:wrapcheck // The error must be returned as is to comply with the resolver contract.
+	}
+
+	md, err := r.src.FindMessage(string(name))
+	if err != nil {
+		return nil, protoregistry.NotFound
+	}
+
+	return dynamicpb.NewMessageType(md), nil
+}
+
+// FindMessageByURL looks up a message by a URL identifier, such as "type.googleapis.com/package.Message".
+func (r *typeResolver) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	name := url
+	if i := strings.LastIndexByte(url, '/'); i >= 0 {
+		name = url[i+1:]
+	}
+
+	return r.FindMessageByName(protoreflect.FullName(name))
+}
+
+// FindExtensionByName looks up an extension field by the field's full name.
+func (r *typeResolver) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	xt, err := protoregistry.GlobalTypes.FindExtensionByName(field)
+	if err == nil || !errors.Is(err, protoregistry.NotFound) {
+		return xt, err //nolint:wrapcheck // The error must be returned as is to comply with the resolver contract.
+	}
+
+	d, err := r.src.FindSymbol(string(field))
+	if err != nil {
+		return nil, protoregistry.NotFound
+	}
+
+	xd, ok := d.(protoreflect.FieldDescriptor)
+	if !ok || !xd.IsExtension() {
+		return nil, protoregistry.NotFound
+	}
+
+	return dynamicpb.NewExtensionType(xd), nil
+}
+
+// FindExtensionByNumber looks up an extension field by the containing message name and the field number.
+func (r *typeResolver) FindExtensionByNumber(
+	message protoreflect.FullName,
+	field protoreflect.FieldNumber,
+) (protoreflect.ExtensionType, error) {
+	xt, err := protoregistry.GlobalTypes.FindExtensionByNumber(message, field)
+	if err == nil || !errors.Is(err, protoregistry.NotFound) {
+		return xt, err //nolint:wrapcheck // The error must be returned as is to comply with the resolver contract.
+	}
+
+	xd, err := r.src.FindExtension(string(message), field)
+	if err != nil {
+		return nil, protoregistry.NotFound
+	}
+
+	return dynamicpb.NewExtensionType(xd), nil
+}
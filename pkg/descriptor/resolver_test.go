@@ -0,0 +1,176 @@
+package descriptor_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+)
+
+const extProto = `
+syntax = "proto2";
+
+package ext;
+
+import "google/protobuf/any.proto";
+
+message Base {
+  optional string name = 1;
+
+  extensions 100 to 200;
+}
+
+message Payload {
+  optional string value = 1;
+}
+
+message Holder {
+  optional google.protobuf.Any any = 1;
+  optional Base base = 2;
+}
+
+extend Base {
+  optional string tag = 100;
+}
+
+message Nested {
+  extend Base {
+    optional Payload payload = 101;
+  }
+}
+`
+
+func newExtSource(t *testing.T) descriptor.Source {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "ext.proto", []byte(extProto), 0o644))
+
+	src, err := descriptor.ProtoFilesSource(context.Background(), fs, nil, []string{"ext.proto"})
+	require.NoError(t, err)
+
+	return src
+}
+
+func TestProtoFilesSource_FindMessage(t *testing.T) {
+	t.Parallel()
+
+	src := newExtSource(t)
+
+	md, err := src.FindMessage("ext.Payload")
+	require.NoError(t, err)
+	require.Equal(t, protoreflect.FullName("ext.Payload"), md.FullName())
+
+	_, err = src.FindMessage("ext.tag")
+	require.ErrorIs(t, err, descriptor.ErrNotAMessage)
+
+	_, err = src.FindMessage("ext.Unknown")
+	require.ErrorIs(t, err, descriptor.ErrSymbolNotFound)
+}
+
+func TestProtoFilesSource_FindExtension(t *testing.T) {
+	t.Parallel()
+
+	src := newExtSource(t)
+
+	tests := []struct {
+		name    string
+		message string
+		number  protoreflect.FieldNumber
+		want    protoreflect.FullName
+		wantErr error
+	}{
+		{
+			name:    "top level extension",
+			message: "ext.Base",
+			number:  100,
+			want:    "ext.tag",
+		},
+		{
+			name:    "nested extension",
+			message: "ext.Base",
+			number:  101,
+			want:    "ext.Nested.payload",
+		},
+		{
+			name:    "unknown number",
+			message: "ext.Base",
+			number:  102,
+			wantErr: descriptor.ErrSymbolNotFound,
+		},
+		{
+			name:    "unknown message",
+			message: "ext.Payload",
+			number:  100,
+			wantErr: descriptor.ErrSymbolNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			xd, err := src.FindExtension(tt.message, tt.number)
+			require.ErrorIs(t, err, tt.wantErr)
+
+			if tt.wantErr == nil {
+				require.Equal(t, tt.want, xd.FullName())
+			}
+		})
+	}
+}
+
+func TestTypeResolver_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	src := newExtSource(t)
+	resolver := descriptor.NewTypeResolver(src)
+
+	md, err := src.FindMessage("ext.Holder")
+	require.NoError(t, err)
+
+	input := `{
+		"any": {"@type": "type.googleapis.com/ext.Payload", "value": "any value"},
+		"base": {"name": "base", "[ext.tag]": "tag", "[ext.Nested.payload]": {"value": "extension value"}}
+	}`
+
+	msg := dynamicpb.NewMessage(md)
+	require.NoError(t, protojson.UnmarshalOptions{Resolver: resolver}.Unmarshal([]byte(input), msg))
+
+	// Extensions must survive the wire format, as if the message has been received from a server.
+	b, err := proto.Marshal(msg)
+	require.NoError(t, err)
+
+	received := dynamicpb.NewMessage(md)
+	require.NoError(t, proto.UnmarshalOptions{Resolver: resolver}.Unmarshal(b, received))
+
+	output, err := protojson.MarshalOptions{Resolver: resolver}.Marshal(received)
+	require.NoError(t, err)
+
+	var want, got any
+	require.NoError(t, json.Unmarshal([]byte(input), &want))
+	require.NoError(t, json.Unmarshal(output, &got))
+	require.Equal(t, want, got)
+}
+
+func TestTypeResolver_NotFound(t *testing.T) {
+	t.Parallel()
+
+	resolver := descriptor.NewTypeResolver(newExtSource(t))
+
+	_, err := resolver.FindMessageByURL("type.googleapis.com/ext.Unknown")
+	require.Error(t, err)
+
+	_, err = resolver.FindExtensionByName("ext.Payload")
+	require.Error(t, err)
+
+	_, err = resolver.FindExtensionByNumber("ext.Base", 150)
+	require.Error(t, err)
+}
@@ -4,17 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"iter"
+	"slices"
+	"sync"
 
 	"github.com/bufbuild/protocompile"
 	"github.com/bufbuild/protocompile/linker"
+	"github.com/bufbuild/protocompile/reporter"
+	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/grpcreflect"
 	"github.com/spf13/afero"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
 
 	fsutil "github.com/heartandu/easyrpc/pkg/fs"
 )
@@ -26,6 +33,14 @@ var (
 	ErrReflectionNotSupported = errors.New("server does not support reflection API")
 	// ErrNotAMethod is returned when requested symbol is not a valid method.
 	ErrNotAMethod = errors.New("selected element is not a method")
+	// ErrNotAMessage is returned when requested symbol is not a valid message.
+	ErrNotAMessage = errors.New("selected element is not a message")
+	// ErrCompilation is returned when proto files cannot be compiled.
+	ErrCompilation = errors.New("failed to compile proto files")
+	// ErrNoProtoFiles is returned when a glob pattern doesn't match any proto files.
+	ErrNoProtoFiles = errors.New("no proto files match the pattern")
+	// ErrInvalidProtoset is returned when protoset files cannot be parsed or contain an incomplete set of files.
+	ErrInvalidProtoset = errors.New("invalid protoset files")
 )
 
 // Source defines the interface for a source of protocol buffer descriptors.
@@ -34,32 +49,82 @@ type Source interface {
 	ListMethods() ([]string, error)
 	FindSymbol(name string) (protoreflect.Descriptor, error)
 	FindMethod(method string) (Method, error)
+	FindMessage(name string) (protoreflect.MessageDescriptor, error)
+	FindExtension(message string, number protoreflect.FieldNumber) (protoreflect.ExtensionDescriptor, error)
 }
 
+// allProtoFiles is a glob pattern matching all proto files under a directory.
+const allProtoFiles = "**/*.proto"
+
 // ReflectionSource creates a source of protocol buffer descriptors using server reflection.
 func ReflectionSource(ctx context.Context, cc grpc.ClientConnInterface) (Source, error) {
-	return &serverReflectionSource{c: grpcreflect.NewClientAuto(ctx, cc)}, nil
+	return &serverReflectionSource{
+		ctx:             ctx,
+		cc:              cc,
+		c:               grpcreflect.NewClientAuto(ctx, cc),
+		serviceFiles:    make(map[string]*desc.FileDescriptor),
+		fetchedProtos:   make(map[string]*descriptorpb.FileDescriptorProto),
+		fetchedServices: make(map[string]struct{}),
+	}, nil
 }
 
 // ProtoFilesSource creates a source of protocol buffer descriptors using proto files.
+// Proto files may be glob patterns, e.g. "**/*.proto", which are matched against files under the import paths.
+// Import paths may point at zip or gzipped tar archives, which are mounted as read-only filesystems.
+// If there are no import paths and the working directory belongs to a buf workspace, its module roots
+// and cached dependencies are used as import paths after the working directory, and glob patterns are matched
+// against the module roots only, ignoring the files excluded from the modules.
 func ProtoFilesSource(ctx context.Context, fs afero.Fs, importPaths, protoFiles []string) (Source, error) {
-	comp := &protocompile.Compiler{
-		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
-			ImportPaths: importPaths,
-			Accessor: func(path string) (io.ReadCloser, error) {
-				p, err := fsutil.ExpandHome(path)
-				if err != nil {
-					return nil, fmt.Errorf("failed to expand home: %w", err)
-				}
+	paths, err := resolveProtoPaths(fs, importPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := expandProtoFiles(paths.roots, protoFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	fds, err := newCompiler(paths).Compile(ctx, files...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCompilation, err)
+	}
+
+	return &protoFilesSource{
+		fds: fds,
+	}, nil
+}
 
-				return fs.Open(p)
-			},
-		}),
+// DiscoveredProtoFilesSource creates a source of protocol buffer descriptors using all proto files
+// under the import paths, or under the buf module roots if there are none. Files that fail to compile are skipped
+// along with the files importing them, unless none of the files compile.
+func DiscoveredProtoFilesSource(ctx context.Context, fs afero.Fs, importPaths []string) (Source, error) {
+	paths, err := resolveProtoPaths(fs, importPaths)
+	if err != nil {
+		return nil, err
 	}
 
-	fds, err := comp.Compile(ctx, protoFiles...)
+	files, err := expandProtoFiles(paths.roots, []string{allProtoFiles})
 	if err != nil {
-		return nil, fmt.Errorf("failed to compile proto files: %w", err)
+		return nil, err
+	}
+
+	comp := newCompiler(paths)
+	// Errors are ignored, so the compilation of the rest of the files continues.
+	comp.Reporter = reporter.NewReporter(func(reporter.ErrorWithPos) error { return nil }, nil)
+
+	results, err := comp.Compile(ctx, files...)
+
+	fds := make(linker.Files, 0, len(results))
+
+	for _, fd := range results {
+		if fd != nil {
+			fds = append(fds, fd)
+		}
+	}
+
+	if len(fds) == 0 && err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCompilation, err)
 	}
 
 	return &protoFilesSource{
@@ -67,6 +132,168 @@ func ProtoFilesSource(ctx context.Context, fs afero.Fs, importPaths, protoFiles
 	}, nil
 }
 
+func newCompiler(paths *protoPaths) *protocompile.Compiler {
+	importPaths := slices.Concat(paths.wd, paths.roots, paths.deps)
+
+	resolvers := make(protocompile.CompositeResolver, 0, len(importPaths)+1)
+	for _, p := range importPaths {
+		resolvers = append(resolvers, p.resolver())
+	}
+
+	// Embedded third-party files are resolved only if they aren't found under the import paths.
+	resolvers = append(resolvers, thirdPartyResolver())
+
+	return &protocompile.Compiler{
+		// Source info keeps comments of the definitions, so they can be printed back.
+		SourceInfoMode: protocompile.SourceInfoStandard,
+		Resolver:       protocompile.WithStandardImports(resolvers),
+	}
+}
+
+// expandProtoFiles replaces glob patterns with the matching files under the import paths.
+// Other proto files are kept as is.
+func expandProtoFiles(importPaths []importPath, protoFiles []string) ([]string, error) {
+	result := make([]string, 0, len(protoFiles))
+	seen := make(map[string]struct{})
+
+	add := func(name string) {
+		if _, ok := seen[name]; !ok {
+			result = append(result, name)
+			seen[name] = struct{}{}
+		}
+	}
+
+	for _, protoFile := range protoFiles {
+		if !fsutil.IsGlob(protoFile) {
+			add(protoFile)
+			continue
+		}
+
+		matched := false
+
+		for _, p := range importPaths {
+			matches, err := p.glob(protoFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to match proto files: %w", err)
+			}
+
+			for _, match := range matches {
+				add(match)
+			}
+
+			matched = matched || len(matches) > 0
+		}
+
+		if !matched {
+			return nil, fmt.Errorf("%w: %q", ErrNoProtoFiles, protoFile)
+		}
+	}
+
+	return result, nil
+}
+
+// ProtosetSource creates a source of protocol buffer descriptors using files
+// with serialized FileDescriptorSet messages, such as produced by "buf build" or "protoc --descriptor_set_out".
+// The files must contain all dependencies of the descriptors, unless they are well-known types.
+func ProtosetSource(fs afero.Fs, protosetFiles []string) (Source, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]struct{})
+
+	for _, name := range protosetFiles {
+		fileSet, err := readProtoset(fs, name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fdp := range fileSet.GetFile() {
+			if _, ok := seen[fdp.GetName()]; ok {
+				continue
+			}
+
+			seen[fdp.GetName()] = struct{}{}
+			set.File = append(set.File, fdp)
+		}
+	}
+
+	return FileDescriptorSetSource(set)
+}
+
+// FileDescriptorSetSource creates a source of protocol buffer descriptors using a FileDescriptorSet.
+// The set must contain all dependencies of the descriptors, unless they are well-known types.
+func FileDescriptorSetSource(set *descriptorpb.FileDescriptorSet) (Source, error) {
+	set = &descriptorpb.FileDescriptorSet{File: slices.Clone(set.GetFile())}
+	seen := make(map[string]struct{}, len(set.GetFile()))
+
+	for _, fdp := range set.GetFile() {
+		seen[fdp.GetName()] = struct{}{}
+	}
+
+	appendWellKnownImports(set, seen)
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProtoset, err)
+	}
+
+	fds := make(linker.Files, 0, len(set.GetFile()))
+
+	for _, fdp := range set.GetFile() {
+		fd, err := files.FindFileByPath(fdp.GetName())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidProtoset, err)
+		}
+
+		file, err := linker.NewFileRecursive(fd)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidProtoset, err)
+		}
+
+		fds = append(fds, file)
+	}
+
+	return &protoFilesSource{
+		fds: fds,
+	}, nil
+}
+
+func appendWellKnownImports(set *descriptorpb.FileDescriptorSet, seen map[string]struct{}) {
+	// The set grows while it's iterated, so imports of the added files are resolved as well.
+	for i := 0; i < len(set.GetFile()); i++ {
+		for _, dep := range set.GetFile()[i].GetDependency() {
+			if _, ok := seen[dep]; ok {
+				continue
+			}
+
+			fd, err := protoregistry.GlobalFiles.FindFileByPath(dep)
+			if err != nil {
+				continue
+			}
+
+			seen[dep] = struct{}{}
+			set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+		}
+	}
+}
+
+func readProtoset(fs afero.Fs, name string) (*descriptorpb.FileDescriptorSet, error) {
+	path, err := fsutil.ExpandHome(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand home: %w", err)
+	}
+
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read protoset file: %w", err)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(b, set); err != nil {
+		return nil, fmt.Errorf("%w: %q: %w", ErrInvalidProtoset, name, err)
+	}
+
+	return set, nil
+}
+
 type protoFilesSource struct {
 	fds linker.Files
 }
@@ -133,8 +360,45 @@ func (s *protoFilesSource) FindMethod(method string) (Method, error) {
 	return nil, ErrNotAMethod
 }
 
+// FindMessage searches for a message with the given name in the protobuf sources.
+func (s *protoFilesSource) FindMessage(name string) (protoreflect.MessageDescriptor, error) {
+	d, err := s.FindSymbol(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find symbol: %w", err)
+	}
+
+	return asMessage(d)
+}
+
+// FindExtension searches for an extension of the given message with the given field number
+// in the protobuf sources and their imports.
+func (s *protoFilesSource) FindExtension(
+	message string,
+	number protoreflect.FieldNumber,
+) (protoreflect.ExtensionDescriptor, error) {
+	visited := make(map[string]struct{})
+
+	for _, fd := range s.fds {
+		if xd := findExtensionInImports(fd, protoreflect.FullName(message), number, visited); xd != nil {
+			return xd, nil
+		}
+	}
+
+	return nil, ErrSymbolNotFound
+}
+
 type serverReflectionSource struct {
-	c *grpcreflect.Client
+	ctx context.Context //nolint:containedctx // Reflection streams are opened with the context of the source.
+	cc  grpc.ClientConnInterface
+	c   *grpcreflect.Client
+
+	mu sync.RWMutex
+	// serviceFiles contains files fetched while listing methods by names of the services they define.
+	serviceFiles map[string]*desc.FileDescriptor
+	// fetchedProtos contains all files fetched while listing methods, including imports, by their names.
+	fetchedProtos map[string]*descriptorpb.FileDescriptorProto
+	// fetchedServices contains names of the services defined in fetchedProtos.
+	fetchedServices map[string]struct{}
 }
 
 // ListServices returns a list of services using server reflection.
@@ -148,43 +412,90 @@ func (s *serverReflectionSource) ListServices() ([]string, error) {
 }
 
 // ListMethods returns a list of methods using server reflection.
+// Files of the services are fetched concurrently, and each file is fetched once,
+// even if it defines several services.
 func (s *serverReflectionSource) ListMethods() ([]string, error) {
-	services, err := s.c.ListServices()
+	services, err := s.ListServices()
 	if err != nil {
-		return nil, reflectWrapErr("failed to query service list", err)
+		return nil, err
+	}
+
+	if err := s.fetchServiceFiles(services); err != nil {
+		return nil, err
 	}
 
 	methods := make([]string, 0)
 
 	for _, service := range services {
-		fd, err := s.c.FileContainingSymbol(service)
-		if err != nil {
-			return nil, reflectWrapErr("failed to query file containing symbol", err)
+		fd := s.serviceFile(service)
+		if fd == nil {
+			continue
 		}
 
-		for _, fds := range fd.GetServices() {
-			for _, md := range fds.GetMethods() {
-				methods = append(methods, md.GetFullyQualifiedName())
-			}
+		sd, ok := fd.FindSymbol(service).(*desc.ServiceDescriptor)
+		if !ok {
+			continue
+		}
+
+		for _, md := range sd.GetMethods() {
+			methods = append(methods, md.GetFullyQualifiedName())
 		}
 	}
 
 	return methods, nil
 }
 
+func (s *serverReflectionSource) serviceFile(service string) *desc.FileDescriptor {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.serviceFiles[service]
+}
+
+func (s *serverReflectionSource) addServiceFile(fd *desc.FileDescriptor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sd := range fd.GetServices() {
+		s.serviceFiles[sd.GetFullyQualifiedName()] = fd
+	}
+}
+
+// findFetchedSymbol searches for a symbol in the files fetched while listing methods.
+func (s *serverReflectionSource) findFetchedSymbol(name string) desc.Descriptor {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, fd := range s.serviceFiles {
+		if d := fd.FindSymbol(name); d != nil {
+			return d
+		}
+	}
+
+	return nil
+}
+
 // FindSymbol finds a symbol using server reflection.
+// Files fetched while listing methods are searched first to avoid querying them again.
 func (s *serverReflectionSource) FindSymbol(name string) (protoreflect.Descriptor, error) {
-	fileDescriptor, err := s.c.FileContainingSymbol(name)
-	if err != nil {
-		return nil, reflectWrapErr("failed to query file containing symbol", err)
-	}
+	d := s.findFetchedSymbol(name)
+	if d == nil {
+		fileDescriptor, err := s.c.FileContainingSymbol(name)
+		if grpcreflect.IsElementNotFoundError(err) {
+			return nil, ErrSymbolNotFound
+		}
 
-	if d := fileDescriptor.FindSymbol(name); d != nil {
-		if wr, ok := d.(interface {
-			Unwrap() protoreflect.Descriptor
-		}); ok {
-			return wr.Unwrap(), nil
+		if err != nil {
+			return nil, reflectWrapErr("failed to query file containing symbol", err)
 		}
+
+		d = fileDescriptor.FindSymbol(name)
+	}
+
+	if wr, ok := d.(interface {
+		Unwrap() protoreflect.Descriptor
+	}); ok {
+		return wr.Unwrap(), nil
 	}
 
 	return nil, ErrSymbolNotFound
@@ -204,6 +515,117 @@ func (s *serverReflectionSource) FindMethod(method string) (Method, error) {
 	return nil, ErrNotAMethod
 }
 
+// FindMessage searches for a message with the given name in the server reflection source.
+func (s *serverReflectionSource) FindMessage(name string) (protoreflect.MessageDescriptor, error) {
+	d, err := s.FindSymbol(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find symbol: %w", err)
+	}
+
+	return asMessage(d)
+}
+
+// FindExtension searches for an extension of the given message with the given field number
+// in the server reflection source.
+func (s *serverReflectionSource) FindExtension(
+	message string,
+	number protoreflect.FieldNumber,
+) (protoreflect.ExtensionDescriptor, error) {
+	fileDescriptor, err := s.c.FileContainingExtension(message, int32(number))
+	if err != nil {
+		return nil, reflectWrapErr("failed to query file containing extension", err)
+	}
+
+	if xd := findExtension(fileDescriptor.UnwrapFile(), protoreflect.FullName(message), number); xd != nil {
+		return xd, nil
+	}
+
+	return nil, ErrSymbolNotFound
+}
+
+func asMessage(d protoreflect.Descriptor) (protoreflect.MessageDescriptor, error) {
+	if md, ok := d.(protoreflect.MessageDescriptor); ok {
+		return md, nil
+	}
+
+	return nil, ErrNotAMessage
+}
+
+// findExtensionInImports searches for an extension in the file and all files it imports.
+// Visited files are tracked to not search the same file twice.
+func findExtensionInImports(
+	fd protoreflect.FileDescriptor,
+	message protoreflect.FullName,
+	number protoreflect.FieldNumber,
+	visited map[string]struct{},
+) protoreflect.ExtensionDescriptor {
+	if _, ok := visited[fd.Path()]; ok {
+		return nil
+	}
+
+	visited[fd.Path()] = struct{}{}
+
+	if xd := findExtension(fd, message, number); xd != nil {
+		return xd
+	}
+
+	imports := fd.Imports()
+	for i := range imports.Len() {
+		if xd := findExtensionInImports(imports.Get(i).FileDescriptor, message, number, visited); xd != nil {
+			return xd
+		}
+	}
+
+	return nil
+}
+
+// findExtension searches for an extension declared in the file, either at the top level or nested in messages.
+func findExtension(
+	fd protoreflect.FileDescriptor,
+	message protoreflect.FullName,
+	number protoreflect.FieldNumber,
+) protoreflect.ExtensionDescriptor {
+	if xd := matchExtension(fd.Extensions(), message, number); xd != nil {
+		return xd
+	}
+
+	return findNestedExtension(fd.Messages(), message, number)
+}
+
+func findNestedExtension(
+	mds protoreflect.MessageDescriptors,
+	message protoreflect.FullName,
+	number protoreflect.FieldNumber,
+) protoreflect.ExtensionDescriptor {
+	for i := range mds.Len() {
+		md := mds.Get(i)
+
+		if xd := matchExtension(md.Extensions(), message, number); xd != nil {
+			return xd
+		}
+
+		if xd := findNestedExtension(md.Messages(), message, number); xd != nil {
+			return xd
+		}
+	}
+
+	return nil
+}
+
+func matchExtension(
+	xds protoreflect.ExtensionDescriptors,
+	message protoreflect.FullName,
+	number protoreflect.FieldNumber,
+) protoreflect.ExtensionDescriptor {
+	for i := range xds.Len() {
+		if xd := xds.Get(i); xd.ContainingMessage().FullName() == message && xd.Number() == number {
+			return xd
+		}
+	}
+
+	return nil
+}
+
 func reflectWrapErr(msg string, err error) error {
 	if err == nil {
 		return nil
@@ -0,0 +1,198 @@
+package descriptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+)
+
+func TestProtosetSourceWellKnownImports(t *testing.T) {
+	t.Parallel()
+
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:       proto.String("wkt.proto"),
+			Package:    proto.String("wkt"),
+			Syntax:     proto.String("proto3"),
+			Dependency: []string{"google/protobuf/empty.proto"},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("Service"),
+				Method: []*descriptorpb.MethodDescriptorProto{{
+					Name:       proto.String("Ping"),
+					InputType:  proto.String(".google.protobuf.Empty"),
+					OutputType: proto.String(".google.protobuf.Empty"),
+				}},
+			}},
+		}},
+	}
+
+	b, err := proto.Marshal(set)
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "wkt.binpb", b, 0o644))
+
+	src, err := descriptor.ProtosetSource(fs, []string{"wkt.binpb"})
+	require.NoError(t, err)
+
+	method, err := src.FindMethod("wkt.Service.Ping")
+	require.NoError(t, err)
+	require.Equal(t, "google.protobuf.Empty", string(method.RequestMessage().ProtoReflect().Descriptor().FullName()))
+}
+
+func newDiscoveryFs(t *testing.T) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+
+	for name, contents := range map[string]string{
+		"protos/api/v1/service.proto": `syntax = "proto3";
+package api.v1;
+import "api/v1/types.proto";
+service Service { rpc Get(Request) returns (Response); }
+`,
+		"protos/api/v1/types.proto": `syntax = "proto3";
+package api.v1;
+message Request {}
+message Response {}
+`,
+		"protos/other/other.proto": `syntax = "proto3";
+package other;
+service Other { rpc Ping(PingMessage) returns (PingMessage); }
+message PingMessage {}
+`,
+		"protos/broken/broken.proto": `syntax = "proto3";
+package broken;
+service Broken { rpc Missing(Unknown) returns (Unknown); }
+`,
+		"protos/broken/importer.proto": `syntax = "proto3";
+package broken;
+import "broken/broken.proto";
+service Importer { rpc Get(Unknown) returns (Unknown); }
+`,
+		"protos/README.md": "not a proto file",
+	} {
+		require.NoError(t, afero.WriteFile(fs, name, []byte(contents), 0o644))
+	}
+
+	return fs
+}
+
+func TestProtoFilesSourceGlob(t *testing.T) {
+	t.Parallel()
+
+	fs := newDiscoveryFs(t)
+
+	src, err := descriptor.ProtoFilesSource(context.Background(), fs, []string{"protos"}, []string{"api/**/*.proto"})
+	require.NoError(t, err)
+
+	services, err := src.ListServices()
+	require.NoError(t, err)
+	require.Equal(t, []string{"api.v1.Service"}, services)
+
+	_, err = descriptor.ProtoFilesSource(context.Background(), fs, []string{"protos"}, []string{"**/*.proto"})
+	require.ErrorIs(t, err, descriptor.ErrCompilation, "broken files must fail explicit globs")
+
+	_, err = descriptor.ProtoFilesSource(context.Background(), fs, []string{"protos"}, []string{"missing/*.proto"})
+	require.ErrorIs(t, err, descriptor.ErrNoProtoFiles)
+}
+
+func TestDiscoveredProtoFilesSource(t *testing.T) {
+	t.Parallel()
+
+	fs := newDiscoveryFs(t)
+
+	src, err := descriptor.DiscoveredProtoFilesSource(context.Background(), fs, []string{"protos"})
+	require.NoError(t, err)
+
+	services, err := src.ListServices()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"api.v1.Service", "other.Other"}, services)
+
+	_, err = src.FindMethod("api.v1.Service.Get")
+	require.NoError(t, err)
+}
+
+func TestDiscoveredProtoFilesSourceAllBroken(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "protos/broken.proto", []byte("syntax = "), 0o644))
+
+	_, err := descriptor.DiscoveredProtoFilesSource(context.Background(), fs, []string{"protos"})
+	require.ErrorIs(t, err, descriptor.ErrCompilation)
+}
+
+func TestProtoFilesSourceThirdPartyImports(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "api.proto", []byte(`syntax = "proto3";
+
+package api;
+
+import "buf/validate/validate.proto";
+import "google/api/annotations.proto";
+import "google/api/field_behavior.proto";
+import "google/rpc/status.proto";
+
+service Service {
+  rpc Get(GetRequest) returns (google.rpc.Status) {
+    option (google.api.http) = {get: "/v1/{name}"};
+  }
+}
+
+message GetRequest {
+  string name = 1 [
+    (google.api.field_behavior) = REQUIRED,
+    (buf.validate.field).string.min_len = 1
+  ];
+}
+`), 0o644))
+
+	src, err := descriptor.ProtoFilesSource(context.Background(), fs, nil, []string{"api.proto"})
+	require.NoError(t, err)
+
+	method, err := src.FindMethod("api.Service.Get")
+	require.NoError(t, err)
+	require.Equal(t, "google.rpc.Status", string(method.ResponseMessage().ProtoReflect().Descriptor().FullName()))
+}
+
+func TestProtoFilesSourceThirdPartyImportsOverride(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "protos/google/rpc/status.proto", []byte(`syntax = "proto3";
+
+package google.rpc;
+
+message Status {
+  string custom = 1;
+}
+`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "protos/api.proto", []byte(`syntax = "proto3";
+
+package api;
+
+import "google/rpc/status.proto";
+
+message Response {
+  google.rpc.Status status = 1;
+}
+`), 0o644))
+
+	src, err := descriptor.ProtoFilesSource(context.Background(), fs, []string{"protos"}, []string{"api.proto"})
+	require.NoError(t, err)
+
+	msg, err := src.FindMessage("api.Response")
+	require.NoError(t, err)
+
+	status := msg.Fields().ByName("status").Message()
+	require.NotNil(t, status.Fields().ByName("custom"), "files from the import paths must take precedence")
+}
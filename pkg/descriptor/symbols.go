@@ -0,0 +1,81 @@
+package descriptor
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ListSymbols returns fully qualified names of services, methods, messages and enums
+// declared in the files that define the services of the source.
+func ListSymbols(src Source) ([]string, error) {
+	services, err := src.ListServices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	visitedFiles := map[string]struct{}{}
+	result := make([]string, 0)
+
+	for _, service := range services {
+		d, err := src.FindSymbol(service)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find service %q: %w", service, err)
+		}
+
+		fd := d.ParentFile()
+		if fd == nil {
+			continue
+		}
+
+		if _, ok := visitedFiles[fd.Path()]; ok {
+			continue
+		}
+
+		visitedFiles[fd.Path()] = struct{}{}
+
+		result = appendFileSymbols(result, fd)
+	}
+
+	return result, nil
+}
+
+func appendFileSymbols(result []string, fd protoreflect.FileDescriptor) []string {
+	services := fd.Services()
+	for i := range services.Len() {
+		sd := services.Get(i)
+		result = append(result, string(sd.FullName()))
+
+		methods := sd.Methods()
+		for j := range methods.Len() {
+			result = append(result, string(methods.Get(j).FullName()))
+		}
+	}
+
+	result = appendMessageSymbols(result, fd.Messages())
+
+	return appendEnumSymbols(result, fd.Enums())
+}
+
+func appendMessageSymbols(result []string, messages protoreflect.MessageDescriptors) []string {
+	for i := range messages.Len() {
+		md := messages.Get(i)
+		if md.IsMapEntry() {
+			continue
+		}
+
+		result = append(result, string(md.FullName()))
+		result = appendMessageSymbols(result, md.Messages())
+		result = appendEnumSymbols(result, md.Enums())
+	}
+
+	return result
+}
+
+func appendEnumSymbols(result []string, enums protoreflect.EnumDescriptors) []string {
+	for i := range enums.Len() {
+		result = append(result, string(enums.Get(i).FullName()))
+	}
+
+	return result
+}
@@ -0,0 +1,29 @@
+package descriptor
+
+import (
+	"embed"
+	"io"
+	"path"
+
+	"github.com/bufbuild/protocompile"
+)
+
+// thirdPartyDir is a directory of the embedded third-party proto files.
+const thirdPartyDir = "thirdparty"
+
+// thirdPartyFiles are commonly imported proto files, which aren't the well-known types,
+// e.g. google/api/annotations.proto, google/rpc/status.proto and buf/validate/validate.proto.
+//
+//go:embed thirdparty/*/*/*.proto
+var thirdPartyFiles embed.FS
+
+// thirdPartyResolver returns a resolver of the embedded third-party proto files.
+// It's meant to be used as a fallback, so the files from the import paths take precedence.
+func thirdPartyResolver() protocompile.Resolver {
+	return &protocompile.SourceResolver{
+		Accessor: func(name string) (io.ReadCloser, error) {
+			//nolint:wrapcheck // The error is wrapped by the compiler.
+			return thirdPartyFiles.Open(path.Join(thirdPartyDir, name))
+		},
+	}
+}
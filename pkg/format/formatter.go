@@ -1,8 +1,21 @@
 package format
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protodelim"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultDelimiter = "\n"
+	yamlDocStart     = "---\n"
+	yamlIndent       = 2
 )
 
 // MessageFormatter is an interface that defines a method for formatting a protobuf message into a string.
@@ -10,6 +23,21 @@ type MessageFormatter interface {
 	Format(msg proto.Message) (string, error)
 }
 
+// Delimited is an interface implemented by message formatters that separate consecutive messages
+// with something other than a newline.
+type Delimited interface {
+	Delimiter() string
+}
+
+// Delimiter returns a string to write after each message formatted by the given formatter.
+func Delimiter(f MessageFormatter) string {
+	if d, ok := f.(Delimited); ok {
+		return d.Delimiter()
+	}
+
+	return defaultDelimiter
+}
+
 // JSONMessageFormatter creates a new MessageFormatter that formats messages as JSON using the provided MarshalOptions.
 func JSONMessageFormatter(out protojson.MarshalOptions) MessageFormatter {
 	return &jsonMessageFormatter{
@@ -23,5 +51,121 @@ type jsonMessageFormatter struct {
 
 // Format formats the given protobuf message as a JSON string using the MarshalOptions provided during creation.
 func (f *jsonMessageFormatter) Format(msg proto.Message) (string, error) {
-	return f.out.Format(msg), nil
+	b, err := f.out.Marshal(msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// TextMessageFormatter creates a new MessageFormatter that formats messages in the protobuf text format.
+// Consecutive messages are separated by an empty line.
+func TextMessageFormatter(out prototext.MarshalOptions) MessageFormatter {
+	return &textMessageFormatter{
+		out: out,
+	}
+}
+
+type textMessageFormatter struct {
+	out prototext.MarshalOptions
+}
+
+// Format formats the given protobuf message in the text format using the MarshalOptions provided during creation.
+func (f *textMessageFormatter) Format(msg proto.Message) (string, error) {
+	b, err := f.out.Marshal(msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// Delimiter returns an empty line separator, since text format messages may span multiple lines.
+func (*textMessageFormatter) Delimiter() string {
+	return "\n\n"
+}
+
+// YAMLMessageFormatter creates a new MessageFormatter that formats messages as YAML documents.
+// Messages are converted to YAML from their JSON representation produced with the provided MarshalOptions,
+// and every message starts with a document separator, so a stream of messages forms a valid multi-document YAML.
+func YAMLMessageFormatter(out protojson.MarshalOptions) MessageFormatter {
+	out.Multiline = false
+
+	return &yamlMessageFormatter{
+		out: out,
+	}
+}
+
+type yamlMessageFormatter struct {
+	out protojson.MarshalOptions
+}
+
+// Format formats the given protobuf message as a YAML document.
+func (f *yamlMessageFormatter) Format(msg proto.Message) (string, error) {
+	b, err := f.out.Marshal(msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	// JSON is a subset of YAML, so decoding it into a node keeps the original order of fields.
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return "", fmt.Errorf("failed to convert message to yaml: %w", err)
+	}
+
+	resetYAMLStyle(&node)
+
+	buf := bytes.NewBufferString(yamlDocStart)
+
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(yamlIndent)
+
+	if err := enc.Encode(&node); err != nil {
+		return "", fmt.Errorf("failed to encode yaml: %w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode yaml: %w", err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// resetYAMLStyle drops the JSON flow and quoting styles, so the node is encoded in the block style.
+// Strings that would otherwise be read as other types are still quoted by the encoder.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+
+	for _, n := range node.Content {
+		resetYAMLStyle(n)
+	}
+}
+
+// BinaryMessageFormatter creates a new MessageFormatter that formats messages in the protobuf wire format,
+// each prefixed with its size encoded as a varint, so a stream of messages can be split back.
+func BinaryMessageFormatter(out proto.MarshalOptions) MessageFormatter {
+	return &binaryMessageFormatter{
+		out: out,
+	}
+}
+
+type binaryMessageFormatter struct {
+	out proto.MarshalOptions
+}
+
+// Format formats the given protobuf message as a size-delimited wire format message.
+func (f *binaryMessageFormatter) Format(msg proto.Message) (string, error) {
+	var buf bytes.Buffer
+
+	if _, err := (protodelim.MarshalOptions{MarshalOptions: f.out}).MarshalTo(&buf, msg); err != nil {
+		return "", fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// Delimiter returns an empty string, since the messages are already delimited by their size.
+func (*binaryMessageFormatter) Delimiter() string {
+	return ""
 }
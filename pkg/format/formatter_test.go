@@ -1,10 +1,16 @@
 package format_test
 
 import (
+	"bufio"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/heartandu/easyrpc/internal/testdata"
 	"github.com/heartandu/easyrpc/pkg/format"
@@ -13,6 +19,63 @@ import (
 func TestJSONMessageFormatter_Format(t *testing.T) {
 	t.Parallel()
 
+	tests := []struct {
+		name    string
+		out     protojson.MarshalOptions
+		msg     proto.Message
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "default",
+			out:  protojson.MarshalOptions{},
+			msg:  &testdata.EchoResponse{Msg: "hi"},
+			want: `{"msg":"hi"}`,
+		},
+		{
+			name:    "unresolvable any",
+			out:     protojson.MarshalOptions{},
+			msg:     &anypb.Any{TypeUrl: "type.googleapis.com/unknown.Message"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			formatter := format.JSONMessageFormatter(tt.out)
+
+			got, err := formatter.Format(tt.msg)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTextMessageFormatter_Format(t *testing.T) {
+	t.Parallel()
+
+	formatter := format.TextMessageFormatter(prototext.MarshalOptions{Multiline: true})
+
+	got, err := formatter.Format(&testdata.EchoResponse{Msg: "hi"})
+	require.NoError(t, err)
+
+	msg := &testdata.EchoResponse{}
+	require.NoError(t, prototext.Unmarshal([]byte(got), msg))
+	require.Equal(t, "hi", msg.GetMsg())
+	require.NotContains(t, got, "\n", "trailing newline must be trimmed")
+	require.Equal(t, "\n\n", format.Delimiter(formatter))
+}
+
+func TestYAMLMessageFormatter_Format(t *testing.T) {
+	t.Parallel()
+
 	tests := []struct {
 		name string
 		out  protojson.MarshalOptions
@@ -23,18 +86,45 @@ func TestJSONMessageFormatter_Format(t *testing.T) {
 			name: "default",
 			out:  protojson.MarshalOptions{},
 			msg:  &testdata.EchoResponse{Msg: "hi"},
-			want: `{"msg":"hi"}`,
+			want: "---\nmsg: hi",
+		},
+		{
+			name: "keeps strings quoted",
+			out:  protojson.MarshalOptions{},
+			msg:  &testdata.EchoResponse{Msg: "123"},
+			want: "---\nmsg: \"123\"",
+		},
+		{
+			name: "emit unpopulated",
+			out:  protojson.MarshalOptions{EmitUnpopulated: true},
+			msg:  &testdata.EchoResponse{},
+			want: "---\nmsg: \"\"",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			formatter := format.JSONMessageFormatter(tt.out)
+			formatter := format.YAMLMessageFormatter(tt.out)
 
 			got, err := formatter.Format(tt.msg)
 			require.NoError(t, err)
 			require.Equal(t, tt.want, got)
+			require.Equal(t, "\n", format.Delimiter(formatter))
 		})
 	}
 }
+
+func TestBinaryMessageFormatter_Format(t *testing.T) {
+	t.Parallel()
+
+	formatter := format.BinaryMessageFormatter(proto.MarshalOptions{})
+
+	got, err := formatter.Format(&testdata.EchoResponse{Msg: "hi"})
+	require.NoError(t, err)
+
+	msg := &testdata.EchoResponse{}
+	require.NoError(t, protodelim.UnmarshalFrom(bufio.NewReader(strings.NewReader(got)), msg))
+	require.Equal(t, "hi", msg.GetMsg())
+	require.Empty(t, format.Delimiter(formatter))
+}
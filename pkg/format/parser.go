@@ -1,23 +1,34 @@
 package format
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 
+	"google.golang.org/protobuf/encoding/protodelim"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
 )
 
+// ErrInvalidInput is returned when the input cannot be parsed into a message.
+var ErrInvalidInput = errors.New("invalid input")
+
 // MessageParser is an interface for parsing requests.
 type MessageParser interface {
 	Next(msg proto.Message) error
 }
 
 // JSONMessageParser creates a new MessageParser for JSON input.
+// Line comments starting with "//" are skipped, e.g. the ones of request skeletons.
 func JSONMessageParser(input io.Reader, unmarshalOpts protojson.UnmarshalOptions) MessageParser {
 	return &jsonMessageParser{
-		decoder: json.NewDecoder(input),
+		decoder: json.NewDecoder(&commentSkippingReader{r: bufio.NewReader(input)}),
 		out:     unmarshalOpts,
 	}
 }
@@ -31,12 +42,283 @@ type jsonMessageParser struct {
 func (p *jsonMessageParser) Next(msg proto.Message) error {
 	var raw json.RawMessage
 	if err := p.decoder.Decode(&raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("failed to read raw input: %w: %w", ErrInvalidInput, err)
+		}
+
 		return fmt.Errorf("failed to read raw input: %w", err)
 	}
 
 	if err := p.out.Unmarshal(raw, msg); err != nil {
-		return fmt.Errorf("failed to unmarshal message: %w", err)
+		return fmt.Errorf("failed to unmarshal message: %w: %w", ErrInvalidInput, err)
+	}
+
+	return nil
+}
+
+// YAMLMessageParser creates a new MessageParser for YAML input.
+// Every YAML document is a separate message, so a stream of messages is separated with "---".
+// Documents are converted to JSON and then unmarshalled using the provided UnmarshalOptions.
+func YAMLMessageParser(input io.Reader, unmarshalOpts protojson.UnmarshalOptions) MessageParser {
+	reader := &errRecordingReader{r: input}
+
+	return &yamlMessageParser{
+		reader:  reader,
+		decoder: yaml.NewDecoder(reader),
+		out:     unmarshalOpts,
+	}
+}
+
+type yamlMessageParser struct {
+	reader  *errRecordingReader
+	decoder *yaml.Decoder
+	out     protojson.UnmarshalOptions
+}
+
+// Next reads a YAML document and unmarshals it into a proto.Message.
+func (p *yamlMessageParser) Next(msg proto.Message) error {
+	var doc any
+	if err := p.decoder.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+
+		// The decoder doesn't wrap errors of the underlying reader, so they are taken from the reader itself.
+		if readErr := p.reader.Err(); readErr != nil {
+			return fmt.Errorf("failed to read raw input: %w", readErr)
+		}
+
+		return fmt.Errorf("failed to read raw input: %w: %w", ErrInvalidInput, err)
+	}
+
+	// An empty document stands for an empty message.
+	if doc == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(yamlToJSON(doc))
+	if err != nil {
+		return fmt.Errorf("failed to convert yaml to json: %w: %w", ErrInvalidInput, err)
+	}
+
+	if err := p.out.Unmarshal(raw, msg); err != nil {
+		return fmt.Errorf("failed to unmarshal message: %w: %w", ErrInvalidInput, err)
 	}
 
 	return nil
 }
+
+// yamlToJSON converts decoded YAML mappings with non-string keys to JSON compatible ones.
+// Such keys are valid in YAML, e.g. for protobuf maps with integer keys, but can't be marshalled to JSON.
+func yamlToJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = yamlToJSON(value)
+		}
+
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+
+		for key, value := range v {
+			m[fmt.Sprint(key)] = yamlToJSON(value)
+		}
+
+		return m
+	case []any:
+		for i, value := range v {
+			v[i] = yamlToJSON(value)
+		}
+
+		return v
+	default:
+		return v
+	}
+}
+
+// TextMessageParser creates a new MessageParser for protobuf text format input.
+// Consecutive messages are separated by an empty line. Comment lines between messages are skipped.
+func TextMessageParser(input io.Reader, unmarshalOpts prototext.UnmarshalOptions) MessageParser {
+	return &textMessageParser{
+		reader: bufio.NewReader(input),
+		out:    unmarshalOpts,
+	}
+}
+
+type textMessageParser struct {
+	reader *bufio.Reader
+	out    prototext.UnmarshalOptions
+}
+
+// Next reads lines up to the next empty line and unmarshals them into a proto.Message.
+func (p *textMessageParser) Next(msg proto.Message) error {
+	var record []byte
+
+	for {
+		line, err := p.reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read raw input: %w", err)
+		}
+
+		trimmed := bytes.TrimSpace(line)
+		blank := len(trimmed) == 0
+
+		// Empty lines and comments before a message are skipped, while an empty line after it ends the message.
+		if !blank && (len(record) > 0 || !bytes.HasPrefix(trimmed, []byte("#"))) {
+			record = append(record, line...)
+		}
+
+		if blank && len(record) > 0 || errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	if len(record) == 0 {
+		return io.EOF
+	}
+
+	if err := p.out.Unmarshal(record, msg); err != nil {
+		return fmt.Errorf("failed to unmarshal message: %w: %w", ErrInvalidInput, err)
+	}
+
+	return nil
+}
+
+// BinaryMessageParser creates a new MessageParser for protobuf wire format input,
+// where every message is prefixed with its size encoded as a varint.
+func BinaryMessageParser(input io.Reader, unmarshalOpts proto.UnmarshalOptions) MessageParser {
+	reader := &errRecordingReader{r: input}
+
+	return &binaryMessageParser{
+		reader:   reader,
+		buffered: bufio.NewReader(reader),
+		out:      protodelim.UnmarshalOptions{UnmarshalOptions: unmarshalOpts},
+	}
+}
+
+type binaryMessageParser struct {
+	reader   *errRecordingReader
+	buffered *bufio.Reader
+	out      protodelim.UnmarshalOptions
+}
+
+// Next reads a size-delimited message and unmarshals it into a proto.Message.
+func (p *binaryMessageParser) Next(msg proto.Message) error {
+	if err := p.out.UnmarshalFrom(p.buffered, msg); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+
+		if readErr := p.reader.Err(); readErr != nil {
+			return fmt.Errorf("failed to read raw input: %w", readErr)
+		}
+
+		return fmt.Errorf("failed to unmarshal message: %w: %w", ErrInvalidInput, err)
+	}
+
+	return nil
+}
+
+// errRecordingReader is a reader that remembers the first error of the underlying reader other than io.EOF.
+// It allows telling failed reads apart from malformed input when a decoder doesn't keep the original error.
+type errRecordingReader struct {
+	r   io.Reader
+	err error
+}
+
+// Read reads from the underlying reader and records its error.
+func (r *errRecordingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) && r.err == nil {
+		r.err = err
+	}
+
+	return n, err //nolint:wrapcheck // This is a simple decorator.
+}
+
+// Err returns the recorded error.
+func (r *errRecordingReader) Err() error {
+	return r.err
+}
+
+// commentSkippingReader is a reader of JSON input, which skips line comments starting with "//" outside of strings.
+type commentSkippingReader struct {
+	r        *bufio.Reader
+	inString bool
+	escaped  bool
+}
+
+// Read reads from the underlying reader, replacing line comments with the line breaks ending them.
+// Already read data is returned without waiting for more of it, so interactive input isn't delayed.
+func (c *commentSkippingReader) Read(p []byte) (int, error) {
+	n := 0
+
+	for n < len(p) && (n == 0 || c.r.Buffered() > 0) {
+		b, err := c.r.ReadByte()
+		if err == nil && !c.inString && b == '/' {
+			if next, peekErr := c.r.Peek(1); peekErr == nil && next[0] == '/' {
+				b, err = c.skipLine()
+			}
+		}
+
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+
+			return 0, err //nolint:wrapcheck // This is a simple decorator.
+		}
+
+		c.track(b)
+		p[n] = b
+		n++
+	}
+
+	return n, nil
+}
+
+// StripLineComments removes line comments starting with "//" outside of strings from the JSON text,
+// along with the trailing spaces preceding them. Lines containing nothing but a comment are removed too.
+func StripLineComments(text string) string {
+	// Reading from a string never fails.
+	stripped, _ := io.ReadAll(&commentSkippingReader{r: bufio.NewReader(strings.NewReader(text))})
+
+	lines := strings.Split(text, "\n")
+	strippedLines := strings.Split(string(stripped), "\n")
+	result := make([]string, 0, len(strippedLines))
+
+	for i, line := range strippedLines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" && i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+			continue
+		}
+
+		result = append(result, line)
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// skipLine skips the rest of the line and returns the line break.
+func (c *commentSkippingReader) skipLine() (byte, error) {
+	for {
+		b, err := c.r.ReadByte()
+		if err != nil || b == '\n' {
+			return b, err //nolint:wrapcheck // The error is returned by Read as is.
+		}
+	}
+}
+
+// track tracks whether the reader is inside of a string.
+func (c *commentSkippingReader) track(b byte) {
+	switch {
+	case c.escaped:
+		c.escaped = false
+	case c.inString && b == '\\':
+		c.escaped = true
+	case b == '"':
+		c.inString = !c.inString
+	}
+}
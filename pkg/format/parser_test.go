@@ -1,13 +1,16 @@
 package format_test
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/heartandu/easyrpc/internal/testdata"
@@ -37,6 +40,24 @@ func TestJSONMessageParser_Parse(t *testing.T) {
 			want:    &testdata.EchoRequest{},
 			wantErr: io.EOF,
 		},
+		{
+			name:    "line comments",
+			input:   strings.NewReader("{ // comment\n  \"msg\": \"hi // \\\" not a comment\" // trailing\n}"),
+			want:    &testdata.EchoRequest{Msg: `hi // " not a comment`},
+			wantErr: nil,
+		},
+		{
+			name:    "malformed json",
+			input:   strings.NewReader(`{"msg":`),
+			want:    &testdata.EchoRequest{},
+			wantErr: format.ErrInvalidInput,
+		},
+		{
+			name:    "unknown field",
+			input:   strings.NewReader(`{"unknown":"hi"}`),
+			want:    &testdata.EchoRequest{},
+			wantErr: format.ErrInvalidInput,
+		},
 		{
 			name: "reader error",
 			input: funcReader(func(p []byte) (int, error) {
@@ -64,6 +85,230 @@ func TestJSONMessageParser_Parse(t *testing.T) {
 	}
 }
 
+func TestStripLineComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "no comments",
+			input: "{\n  \"msg\": \"hi\"\n}",
+			want:  "{\n  \"msg\": \"hi\"\n}",
+		},
+		{
+			name:  "trailing comments",
+			input: "{\n  \"msg\": \"hi\", // greeting\n  \"mode\": \"LOUD\" // one of: QUIET, LOUD\n}",
+			want:  "{\n  \"msg\": \"hi\",\n  \"mode\": \"LOUD\"\n}",
+		},
+		{
+			name:  "comment lines",
+			input: "// header\n{\n  // one of: QUIET, LOUD\n  \"modes\": {}\n}\n\n{}",
+			want:  "{\n  \"modes\": {}\n}\n\n{}",
+		},
+		{
+			name:  "comments inside of strings",
+			input: `{"msg": "hi // \" not a comment"} // comment`,
+			want:  `{"msg": "hi // \" not a comment"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, format.StripLineComments(tt.input))
+		})
+	}
+}
+
+func TestYAMLMessageParser_Parse(t *testing.T) {
+	t.Parallel()
+
+	testErr := errors.New("oh no")
+
+	tests := []struct {
+		name    string
+		input   io.Reader
+		want    []*testdata.EchoRequest
+		wantErr error
+	}{
+		{
+			name:  "success",
+			input: strings.NewReader("msg: hi"),
+			want:  []*testdata.EchoRequest{{Msg: "hi"}},
+		},
+		{
+			name:  "multiple documents",
+			input: strings.NewReader("---\nmsg: hi\n---\nmsg: \"123\"\n---\nmsg: 2024-01-01T00:00:00Z\n"),
+			want: []*testdata.EchoRequest{
+				{Msg: "hi"},
+				{Msg: "123"},
+				{Msg: "2024-01-01T00:00:00Z"},
+			},
+		},
+		{
+			name:  "json document",
+			input: strings.NewReader(`{"msg":"hi"}`),
+			want:  []*testdata.EchoRequest{{Msg: "hi"}},
+		},
+		{
+			name:  "empty document",
+			input: strings.NewReader("---\n---\nmsg: hi"),
+			want:  []*testdata.EchoRequest{{}, {Msg: "hi"}},
+		},
+		{
+			name:    "malformed yaml",
+			input:   strings.NewReader("msg: [hi"),
+			wantErr: format.ErrInvalidInput,
+		},
+		{
+			name:    "unknown field",
+			input:   strings.NewReader("unknown: hi"),
+			wantErr: format.ErrInvalidInput,
+		},
+		{
+			name: "reader error",
+			input: funcReader(func(p []byte) (int, error) {
+				return 0, testErr
+			}),
+			wantErr: testErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := format.YAMLMessageParser(tt.input, protojson.UnmarshalOptions{})
+
+			requireParsed(t, parser, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestTextMessageParser_Parse(t *testing.T) {
+	t.Parallel()
+
+	testErr := errors.New("oh no")
+
+	tests := []struct {
+		name    string
+		input   io.Reader
+		want    []*testdata.EchoRequest
+		wantErr error
+	}{
+		{
+			name:  "success",
+			input: strings.NewReader(`msg: "hi"`),
+			want:  []*testdata.EchoRequest{{Msg: "hi"}},
+		},
+		{
+			name:  "multiple messages",
+			input: strings.NewReader("\nmsg: \"hi\"\n\n\n  \nmsg:\n  \"there\"\n\n"),
+			want:  []*testdata.EchoRequest{{Msg: "hi"}, {Msg: "there"}},
+		},
+		{
+			name:  "comments between messages",
+			input: strings.NewReader("# first\n\nmsg: \"hi\" # greeting\n\n# second\nmsg: \"there\"\n\n# trailing\n"),
+			want:  []*testdata.EchoRequest{{Msg: "hi"}, {Msg: "there"}},
+		},
+		{
+			name:    "malformed text",
+			input:   strings.NewReader(`msg: "hi`),
+			wantErr: format.ErrInvalidInput,
+		},
+		{
+			name: "reader error",
+			input: funcReader(func(p []byte) (int, error) {
+				return 0, testErr
+			}),
+			wantErr: testErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := format.TextMessageParser(tt.input, prototext.UnmarshalOptions{})
+
+			requireParsed(t, parser, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestBinaryMessageParser_Parse(t *testing.T) {
+	t.Parallel()
+
+	testErr := errors.New("oh no")
+
+	var stream bytes.Buffer
+
+	for _, msg := range []string{"hi", "there"} {
+		_, err := protodelim.MarshalTo(&stream, &testdata.EchoRequest{Msg: msg})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name    string
+		input   io.Reader
+		want    []*testdata.EchoRequest
+		wantErr error
+	}{
+		{
+			name:  "multiple messages",
+			input: bytes.NewReader(stream.Bytes()),
+			want:  []*testdata.EchoRequest{{Msg: "hi"}, {Msg: "there"}},
+		},
+		{
+			name:    "truncated message",
+			input:   bytes.NewReader(stream.Bytes()[:3]),
+			wantErr: format.ErrInvalidInput,
+		},
+		{
+			name:    "malformed message",
+			input:   bytes.NewReader([]byte{2, 0xff, 0xff}),
+			wantErr: format.ErrInvalidInput,
+		},
+		{
+			name: "reader error",
+			input: funcReader(func(p []byte) (int, error) {
+				return 0, testErr
+			}),
+			wantErr: testErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := format.BinaryMessageParser(tt.input, proto.UnmarshalOptions{})
+
+			requireParsed(t, parser, tt.want, tt.wantErr)
+		})
+	}
+}
+
+// requireParsed checks that the parser returns the wanted messages followed by the wanted error or io.EOF.
+func requireParsed(t *testing.T, parser format.MessageParser, want []*testdata.EchoRequest, wantErr error) {
+	t.Helper()
+
+	if wantErr == nil {
+		wantErr = io.EOF
+	}
+
+	for _, wantMsg := range want {
+		got := &testdata.EchoRequest{}
+		require.NoError(t, parser.Next(got))
+
+		if !proto.Equal(got, wantMsg) {
+			t.Errorf("Parse() got = %v, want = %v", got, wantMsg)
+		}
+	}
+
+	require.ErrorIs(t, parser.Next(&testdata.EchoRequest{}), wantErr)
+}
+
 type funcReader func(p []byte) (int, error)
 
 func (f funcReader) Read(p []byte) (int, error) {
@@ -0,0 +1,249 @@
+package format
+
+import (
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// skeletonIndent is an indentation of the nested skeleton values.
+const skeletonIndent = "  "
+
+// SkeletonOptions configures a generated message skeleton.
+type SkeletonOptions struct {
+	// MaxDepth limits the nesting of populated messages. Messages nested deeper are left empty.
+	MaxDepth int
+	// UseProtoNames uses proto field names instead of lowerCamelCase JSON names.
+	UseProtoNames bool
+	// Comments lists allowed enum values and alternatives of oneofs in line comments,
+	// which are only meant for the text edited by the user.
+	Comments bool
+}
+
+// JSONSkeleton returns a JSON skeleton of the message with every field populated with a sample value.
+// Nested messages are populated up to the depth limit, repeated fields and maps get one sample element.
+// Only the first field of every oneof is populated. If enabled, allowed enum values and alternatives of oneofs
+// are listed in line comments, which are skipped by the JSON message parser.
+func JSONSkeleton(md protoreflect.MessageDescriptor, opts SkeletonOptions) string {
+	s := &skeleton{opts: opts}
+
+	return s.message(md, 0, "")
+}
+
+type skeleton struct {
+	opts SkeletonOptions
+}
+
+// member is a field of a JSON object in the skeleton.
+type member struct {
+	key     string
+	value   string
+	comment string
+}
+
+// message returns a skeleton of the message, which nested values are indented relative to indent.
+func (s *skeleton) message(md protoreflect.MessageDescriptor, depth int, indent string) string {
+	if v, ok := s.wellKnownType(md, depth, indent); ok {
+		return v
+	}
+
+	if depth >= s.opts.MaxDepth {
+		return "{}"
+	}
+
+	fields := md.Fields()
+	members := make([]member, 0, fields.Len())
+
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+
+		oneof := fd.ContainingOneof()
+		if oneof != nil && !oneof.IsSynthetic() && oneof.Fields().Get(0) != fd {
+			continue
+		}
+
+		members = append(members, member{
+			key:     s.fieldName(fd),
+			value:   s.field(fd, depth, indent+skeletonIndent),
+			comment: s.comment(fd),
+		})
+	}
+
+	return object(members, indent)
+}
+
+// comment returns a line comment of the field, or an empty string if there is nothing to comment or comments
+// are disabled.
+func (s *skeleton) comment(fd protoreflect.FieldDescriptor) string {
+	if !s.opts.Comments {
+		return ""
+	}
+
+	var comments []string
+
+	if oneof := fd.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+		comments = append(comments, s.oneofComment(oneof))
+	}
+
+	if comment := enumComment(fd); comment != "" {
+		comments = append(comments, comment)
+	}
+
+	return strings.Join(comments, "; ")
+}
+
+// field returns a skeleton of the field value, including lists and maps.
+func (s *skeleton) field(fd protoreflect.FieldDescriptor, depth int, indent string) string {
+	switch {
+	case fd.IsMap():
+		return object([]member{{
+			key:   mapKey(fd.MapKey()),
+			value: s.value(fd.MapValue(), depth, indent+skeletonIndent),
+		}}, indent)
+	case fd.IsList():
+		inner := indent + skeletonIndent
+
+		return "[\n" + inner + s.value(fd, depth, inner) + "\n" + indent + "]"
+	default:
+		return s.value(fd, depth, indent)
+	}
+}
+
+// value returns a sample value of a single element of the field.
+func (s *skeleton) value(fd protoreflect.FieldDescriptor, depth int, indent string) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return "false"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// 64-bit integers are represented as strings in JSON.
+		return `"0"`
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return `""`
+	case protoreflect.EnumKind:
+		if fd.Enum().FullName() == "google.protobuf.NullValue" {
+			return "null"
+		}
+
+		return strconv.Quote(string(fd.Enum().Values().Get(0).Name()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return s.message(fd.Message(), depth+1, indent)
+	default:
+		return "0"
+	}
+}
+
+// wellKnownType returns a sample value of a well-known type, which has a special JSON representation.
+func (s *skeleton) wellKnownType(md protoreflect.MessageDescriptor, depth int, indent string) (string, bool) {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return `"1970-01-01T00:00:00Z"`, true
+	case "google.protobuf.Duration":
+		return `"0s"`, true
+	case "google.protobuf.FieldMask":
+		return `""`, true
+	case "google.protobuf.Struct", "google.protobuf.Any", "google.protobuf.Empty":
+		return "{}", true
+	case "google.protobuf.ListValue":
+		return "[]", true
+	case "google.protobuf.Value":
+		return "null", true
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue",
+		"google.protobuf.Int64Value", "google.protobuf.UInt64Value",
+		"google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		return s.value(md.Fields().ByName("value"), depth, indent), true
+	default:
+		return "", false
+	}
+}
+
+// fieldName returns the name of the field in JSON.
+func (s *skeleton) fieldName(fd protoreflect.FieldDescriptor) string {
+	if s.opts.UseProtoNames {
+		return string(fd.Name())
+	}
+
+	return fd.JSONName()
+}
+
+// oneofComment returns a comment listing the alternatives of the oneof.
+func (s *skeleton) oneofComment(oneof protoreflect.OneofDescriptor) string {
+	fields := oneof.Fields()
+	names := make([]string, 0, fields.Len())
+
+	for i := range fields.Len() {
+		names = append(names, s.fieldName(fields.Get(i)))
+	}
+
+	return "oneof " + string(oneof.Name()) + ", set only one of: " + strings.Join(names, ", ")
+}
+
+// enumComment returns a comment listing the allowed values of the enum field, or of the map values.
+func enumComment(fd protoreflect.FieldDescriptor) string {
+	if fd.IsMap() {
+		fd = fd.MapValue()
+	}
+
+	ed := fd.Enum()
+	if ed == nil || ed.FullName() == "google.protobuf.NullValue" {
+		return ""
+	}
+
+	values := ed.Values()
+	names := make([]string, 0, values.Len())
+
+	for i := range values.Len() {
+		names = append(names, string(values.Get(i).Name()))
+	}
+
+	return "one of: " + strings.Join(names, ", ")
+}
+
+// mapKey returns a sample key of a map. Keys of every type are represented as strings in JSON.
+func mapKey(fd protoreflect.FieldDescriptor) string {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return "key"
+	case protoreflect.BoolKind:
+		return "false"
+	default:
+		return "0"
+	}
+}
+
+// object returns a JSON object of the members, which closing brace is indented with indent.
+func object(members []member, indent string) string {
+	if len(members) == 0 {
+		return "{}"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("{\n")
+
+	for i, m := range members {
+		// Comments of multiline values are placed above them, so they aren't lost after the closing bracket.
+		multiline := strings.Contains(m.value, "\n")
+		if m.comment != "" && multiline {
+			sb.WriteString(indent + skeletonIndent + "// " + m.comment + "\n")
+		}
+
+		sb.WriteString(indent + skeletonIndent + strconv.Quote(m.key) + ": " + m.value)
+
+		if i < len(members)-1 {
+			sb.WriteString(",")
+		}
+
+		if m.comment != "" && !multiline {
+			sb.WriteString(" // " + m.comment)
+		}
+
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(indent + "}")
+
+	return sb.String()
+}
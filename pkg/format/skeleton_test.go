@@ -0,0 +1,152 @@
+package format_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/heartandu/easyrpc/pkg/format"
+)
+
+const skeletonProto = `syntax = "proto3";
+
+package skeleton;
+
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+
+enum Kind {
+  KIND_UNSPECIFIED = 0;
+  KIND_FORMAL = 1;
+}
+
+message Request {
+  string name = 1;
+  int64 id = 2;
+  Kind kind = 3;
+  Address address = 4;
+  repeated Address history = 5;
+  map<string, Kind> kinds = 6;
+  oneof contact {
+    string email = 7;
+    Address mail = 8;
+  }
+  google.protobuf.Timestamp created_at = 9;
+  google.protobuf.Int32Value limit = 10;
+  Node tree = 11;
+  optional bool verbose = 12;
+}
+
+message Address {
+  string city = 1;
+  repeated string lines = 2;
+}
+
+message Node {
+  Node child = 1;
+}
+`
+
+func TestJSONSkeleton(t *testing.T) {
+	t.Parallel()
+
+	md := compileSkeletonMessage(t, "skeleton.Request")
+
+	tests := []struct {
+		name string
+		opts format.SkeletonOptions
+		want string
+	}{
+		{
+			name: "nested messages with comments",
+			opts: format.SkeletonOptions{MaxDepth: 2, Comments: true},
+			want: `{
+  "name": "",
+  "id": "0",
+  "kind": "KIND_UNSPECIFIED", // one of: KIND_UNSPECIFIED, KIND_FORMAL
+  "address": {
+    "city": "",
+    "lines": [
+      ""
+    ]
+  },
+  "history": [
+    {
+      "city": "",
+      "lines": [
+        ""
+      ]
+    }
+  ],
+  // one of: KIND_UNSPECIFIED, KIND_FORMAL
+  "kinds": {
+    "key": "KIND_UNSPECIFIED"
+  },
+  "email": "", // oneof contact, set only one of: email, mail
+  "createdAt": "1970-01-01T00:00:00Z",
+  "limit": 0,
+  "tree": {
+    "child": {}
+  },
+  "verbose": false
+}`,
+		},
+		{
+			name: "proto names with depth limit",
+			opts: format.SkeletonOptions{MaxDepth: 1, UseProtoNames: true},
+			want: `{
+  "name": "",
+  "id": "0",
+  "kind": "KIND_UNSPECIFIED",
+  "address": {},
+  "history": [
+    {}
+  ],
+  "kinds": {
+    "key": "KIND_UNSPECIFIED"
+  },
+  "email": "",
+  "created_at": "1970-01-01T00:00:00Z",
+  "limit": 0,
+  "tree": {},
+  "verbose": false
+}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := format.JSONSkeleton(md, tt.opts)
+			require.Equal(t, tt.want, got)
+
+			msg := dynamicpb.NewMessage(md)
+			parser := format.JSONMessageParser(strings.NewReader(got), protojson.UnmarshalOptions{})
+			require.NoError(t, parser.Next(msg), "skeleton must be a valid request")
+		})
+	}
+}
+
+func compileSkeletonMessage(t *testing.T, name protoreflect.FullName) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	comp := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{"skeleton.proto": skeletonProto}),
+		}),
+	}
+
+	files, err := comp.Compile(context.Background(), "skeleton.proto")
+	require.NoError(t, err)
+
+	md := files[0].Messages().ByName(name.Name())
+	require.NotNil(t, md)
+
+	return md
+}
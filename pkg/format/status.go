@@ -0,0 +1,56 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	_ "google.golang.org/genproto/googleapis/rpc/errdetails" // Register standard error details types.
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// StatusFormatter is an interface that defines a method for formatting a gRPC status into a string.
+type StatusFormatter interface {
+	Format(st *status.Status) (string, error)
+}
+
+// JSONStatusFormatter creates a new StatusFormatter that formats status details as JSON
+// using the provided MarshalOptions. The options resolver is used to decode the details types.
+func JSONStatusFormatter(out protojson.MarshalOptions) StatusFormatter {
+	return &jsonStatusFormatter{
+		out: out,
+	}
+}
+
+type jsonStatusFormatter struct {
+	out protojson.MarshalOptions
+}
+
+// Format formats the status code, message and each of the status details.
+// Details which types cannot be resolved are printed with their type URLs only.
+func (f *jsonStatusFormatter) Format(st *status.Status) (string, error) {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Status code: %s\n", st.Code())
+	fmt.Fprintf(&sb, "Status message: %s\n", st.Message())
+
+	details := st.Proto().GetDetails()
+	if len(details) == 0 {
+		return sb.String(), nil
+	}
+
+	sb.WriteString("Status details:\n")
+
+	for _, detail := range details {
+		b, err := f.out.Marshal(detail)
+		if err != nil {
+			fmt.Fprintf(&sb, "%s: failed to decode: %v\n", detail.GetTypeUrl(), err)
+			continue
+		}
+
+		sb.Write(b)
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,72 @@
+package format_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/heartandu/easyrpc/pkg/format"
+)
+
+func TestJSONStatusFormatter_Format(t *testing.T) {
+	t.Parallel()
+
+	withDetails, err := status.New(codes.NotFound, "not found").WithDetails(&errdetails.ErrorInfo{Reason: "MISSING"})
+	require.NoError(t, err)
+
+	unknownDetails := status.FromProto(&spb.Status{
+		Code:    int32(codes.Unavailable),
+		Message: "unavailable",
+		Details: []*anypb.Any{{TypeUrl: "type.googleapis.com/unknown.Type"}},
+	})
+
+	tests := []struct {
+		name     string
+		st       *status.Status
+		want     string
+		contains []string
+	}{
+		{
+			name: "without details",
+			st:   status.New(codes.Internal, "oops"),
+			want: "Status code: Internal\nStatus message: oops\n",
+		},
+		{
+			name: "with details",
+			st:   withDetails,
+			want: "Status code: NotFound\nStatus message: not found\nStatus details:\n",
+			contains: []string{
+				`"@type":"type.googleapis.com/google.rpc.ErrorInfo"`,
+				`"reason":"MISSING"`,
+			},
+		},
+		{
+			name: "unresolvable details",
+			st:   unknownDetails,
+			want: "Status code: Unavailable\nStatus message: unavailable\nStatus details:\n" +
+				"type.googleapis.com/unknown.Type: failed to decode: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			formatter := format.JSONStatusFormatter(protojson.MarshalOptions{})
+
+			got, err := formatter.Format(tt.st)
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(got, tt.want), "got = %q, want prefix = %q", got, tt.want)
+
+			for _, want := range tt.contains {
+				require.Contains(t, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package fs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	// archiveDirPerm is the permission of directories extracted from archives.
+	archiveDirPerm = 0o755
+	// maxArchiveFiles is the maximum number of proto files extracted from an archive.
+	maxArchiveFiles = 50_000
+	// maxArchiveSize is the maximum total size of proto files extracted from an archive.
+	maxArchiveSize = 64 << 20
+)
+
+var (
+	// ErrUnsupportedArchive is returned when a file is not a zip or a gzipped tar archive.
+	ErrUnsupportedArchive = errors.New("unsupported archive")
+	// ErrInvalidArchiveEntry is returned when an archive entry escapes the archive root.
+	ErrInvalidArchiveEntry = errors.New("invalid archive entry")
+	// ErrArchiveTooLarge is returned when an archive contains too many or too large proto files.
+	ErrArchiveTooLarge = errors.New("archive is too large")
+)
+
+// archiveExts are the extensions of the supported archives.
+var archiveExts = []string{".zip", ".tar.gz", ".tgz"}
+
+// IsArchive reports whether the path has an extension of a supported archive.
+func IsArchive(name string) bool {
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SplitArchivePath splits a path, which points at an archive or a directory inside of it, e.g. "schema.zip/proto",
+// into the archive path and the slash-separated directory inside of the archive. The directory is "." if the path
+// points at the archive itself. It returns false if none of the path elements is an archive.
+func SplitArchivePath(name string) (string, string, bool) {
+	slashed := filepath.ToSlash(name)
+
+	for i := range len(slashed) + 1 {
+		if i < len(slashed) && slashed[i] != '/' {
+			continue
+		}
+
+		if archive := slashed[:i]; IsArchive(archive) {
+			dir := path.Clean("./" + slashed[i:])
+
+			return filepath.FromSlash(archive), dir, true
+		}
+	}
+
+	return "", "", false
+}
+
+// OpenArchive extracts proto files of a zip or a gzipped tar archive into memory
+// and returns them as a read-only filesystem. The paths inside of the filesystem are relative to the archive root.
+// Archives with entries escaping the root, or with too many or too large proto files, are rejected.
+func OpenArchive(afs afero.Fs, name string) (afero.Fs, error) {
+	b, err := afero.ReadFile(afs, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	e := &extractor{fs: afero.NewMemMapFs()}
+
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		err = e.extractZip(b)
+	case IsArchive(name):
+		err = e.extractTarGz(b)
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnsupportedArchive, name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return afero.NewReadOnlyFs(e.fs), nil
+}
+
+// extractor writes proto files of an archive into a filesystem, keeping track of the extracted files.
+type extractor struct {
+	fs afero.Fs
+	// files is the number of extracted files.
+	files int
+	// size is the total size of the extracted files.
+	size int64
+}
+
+// extractZip writes proto files of the zip archive into the filesystem.
+func (e *extractor) extractZip(b []byte) error {
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return fmt.Errorf("failed to open zip archive: %w", err)
+	}
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		if err := e.extractZipFile(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// extractZipFile writes the zip archive file into the filesystem if it's a proto file.
+func (e *extractor) extractZipFile(f *zip.File) error {
+	ok, err := isProtoEntry(f.Name)
+	if err != nil || !ok {
+		return err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	return e.writeEntry(f.Name, rc)
+}
+
+// extractTarGz writes proto files of the gzipped tar archive into the filesystem.
+func (e *extractor) extractTarGz(b []byte) error {
+	gr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to open gzip archive: %w", err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %w", err)
+		}
+
+		// Links and special files can't be proto sources.
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		ok, err := isProtoEntry(hdr.Name)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			if err := e.writeEntry(hdr.Name, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writeEntry creates a file of the archive entry along with its parent directories.
+// The limits of the number and the total size of the extracted files are enforced while the entry is read,
+// so the sizes declared in the archive can't be used to bypass them.
+func (e *extractor) writeEntry(name string, r io.Reader) error {
+	e.files++
+	if e.files > maxArchiveFiles {
+		return fmt.Errorf("%w: more than %d proto files", ErrArchiveTooLarge, maxArchiveFiles)
+	}
+
+	p := entryPath(name)
+
+	if err := e.fs.MkdirAll(filepath.Dir(p), archiveDirPerm); err != nil {
+		return fmt.Errorf("failed to create %q: %w", name, err)
+	}
+
+	lr := &io.LimitedReader{R: r, N: maxArchiveSize - e.size + 1}
+
+	if err := afero.WriteReader(e.fs, p, lr); err != nil {
+		return fmt.Errorf("failed to extract %q: %w", name, err)
+	}
+
+	e.size = maxArchiveSize + 1 - lr.N
+	if e.size > maxArchiveSize {
+		return fmt.Errorf("%w: proto files exceed %d bytes", ErrArchiveTooLarge, maxArchiveSize)
+	}
+
+	return nil
+}
+
+// isProtoEntry reports whether the archive entry is a proto file.
+// An entry referencing a parent directory is an error, since it's meant to escape the archive root.
+func isProtoEntry(name string) (bool, error) {
+	for _, elem := range strings.FieldsFunc(name, isSeparator) {
+		if elem == ".." {
+			return false, fmt.Errorf("%w: %q", ErrInvalidArchiveEntry, name)
+		}
+	}
+
+	return path.Ext(name) == ".proto", nil
+}
+
+// isSeparator reports whether the rune separates path elements of archive entries, which may be created on Windows.
+func isSeparator(r rune) bool {
+	return r == '/' || r == '\\'
+}
+
+// entryPath returns a path of the archive entry relative to the archive root.
+func entryPath(name string) string {
+	return filepath.FromSlash(strings.TrimPrefix(path.Clean("/"+name), "/"))
+}
@@ -0,0 +1,205 @@
+package fs_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heartandu/easyrpc/pkg/fs"
+)
+
+func TestSplitArchivePath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		path        string
+		wantArchive string
+		wantDir     string
+		wantOk      bool
+	}{
+		{
+			name:   "directory",
+			path:   "path/to/proto",
+			wantOk: false,
+		},
+		{
+			name:        "archive",
+			path:        "path/to/schema.zip",
+			wantArchive: "path/to/schema.zip",
+			wantDir:     ".",
+			wantOk:      true,
+		},
+		{
+			name:        "directory inside of an archive",
+			path:        "/releases/schema-v1.tar.gz/schema-v1/proto/",
+			wantArchive: "/releases/schema-v1.tar.gz",
+			wantDir:     "schema-v1/proto",
+			wantOk:      true,
+		},
+		{
+			name:        "short tar extension",
+			path:        "schema.tgz",
+			wantArchive: "schema.tgz",
+			wantDir:     ".",
+			wantOk:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			archive, dir, ok := fs.SplitArchivePath(tt.path)
+			require.Equal(t, tt.wantOk, ok)
+			require.Equal(t, tt.wantArchive, archive)
+			require.Equal(t, tt.wantDir, dir)
+		})
+	}
+}
+
+func TestOpenArchive(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"proto/api/v1/service.proto": "service",
+		"proto/types.proto":          "types",
+		"proto/README.md":            "readme",
+	}
+
+	afs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(afs, "schema.zip", zipArchive(t, files), 0o644))
+	require.NoError(t, afero.WriteFile(afs, "schema.tar.gz", tarGzArchive(t, files), 0o644))
+	require.NoError(t, afero.WriteFile(afs, "schema.rar", nil, 0o644))
+
+	escaping := map[string]string{"proto/../../escape.proto": "escape"}
+	require.NoError(t, afero.WriteFile(afs, "escape.zip", zipArchive(t, escaping), 0o644))
+	require.NoError(t, afero.WriteFile(afs, "escape.tar.gz", tarGzArchive(t, escaping), 0o644))
+
+	for _, name := range []string{"schema.zip", "schema.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			archiveFs, err := fs.OpenArchive(afs, name)
+			require.NoError(t, err)
+
+			b, err := afero.ReadFile(archiveFs, "proto/api/v1/service.proto")
+			require.NoError(t, err)
+			require.Equal(t, "service", string(b))
+
+			matches, err := fs.Glob(archiveFs, "proto", "**/*.proto")
+			require.NoError(t, err)
+			require.Equal(t, []string{"api/v1/service.proto", "types.proto"}, matches)
+
+			exists, err := afero.Exists(archiveFs, "proto/README.md")
+			require.NoError(t, err)
+			require.False(t, exists, "only proto files must be extracted")
+
+			require.Error(t, afero.WriteFile(archiveFs, "proto/new.proto", nil, 0o644), "archive must be read-only")
+		})
+	}
+
+	for _, name := range []string{"escape.zip", "escape.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := fs.OpenArchive(afs, name)
+			require.ErrorIs(t, err, fs.ErrInvalidArchiveEntry)
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := fs.OpenArchive(afs, "schema.rar")
+		require.ErrorIs(t, err, fs.ErrUnsupportedArchive)
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		t.Parallel()
+
+		corruptedFs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(corruptedFs, "schema.zip", []byte("not a zip"), 0o644))
+
+		_, err := fs.OpenArchive(corruptedFs, "schema.zip")
+		require.Error(t, err)
+	})
+}
+
+func TestOpenArchiveTooLarge(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	// The declared size is within the limit, while the total size of the files isn't.
+	const size = 40 << 20
+
+	for _, name := range []string{"a.proto", "b.proto"} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: size, Typeflag: tar.TypeReg}))
+
+		_, err := tw.Write(make([]byte, size))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	afs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(afs, "large.tar.gz", buf.Bytes(), 0o644))
+
+	_, err := fs.OpenArchive(afs, "large.tar.gz")
+	require.ErrorIs(t, err, fs.ErrArchiveTooLarge)
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w := zip.NewWriter(&buf)
+
+	for name, contents := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+
+		_, err = f.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func tarGzArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(contents)),
+			Typeflag: tar.TypeReg,
+		}))
+
+		_, err := tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	return buf.Bytes()
+}
@@ -0,0 +1,97 @@
+package fs
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+const anyDirs = "**"
+
+// IsGlob reports whether the pattern contains any glob metacharacters.
+func IsGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// Glob returns slash-separated paths of files under the root directory, relative to it, matching the pattern.
+// In addition to the path.Match syntax, a "**" path element matches any number of directories.
+// The root directory may start with a tilde, which is expanded to the user's home directory.
+func Glob(afs afero.Fs, root, pattern string) ([]string, error) {
+	dir, err := ExpandHome(root)
+	if err != nil {
+		return nil, err
+	}
+
+	patternElems := strings.Split(pattern, "/")
+
+	// Validate the pattern upfront, since path.Match only reports malformed patterns on a mismatch.
+	for _, elem := range patternElems {
+		if _, err := path.Match(elem, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	matches := make([]string, 0)
+
+	err = afero.Walk(afs, dir, func(p string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err //nolint:wrapcheck // The error is wrapped after the walk.
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		if matchElems(patternElems, strings.Split(rel, "/")) {
+			matches = append(matches, rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %q: %w", root, err)
+	}
+
+	slices.Sort(matches)
+
+	return matches, nil
+}
+
+// matchElems reports whether the path elements match the pattern elements.
+func matchElems(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == anyDirs {
+		for i := range len(name) + 1 {
+			if matchElems(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+
+	return matchElems(pattern[1:], name[1:])
+}
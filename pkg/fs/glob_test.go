@@ -0,0 +1,90 @@
+package fs_test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heartandu/easyrpc/pkg/fs"
+)
+
+func TestIsGlob(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, fs.IsGlob("**/*.proto"))
+	require.True(t, fs.IsGlob("api/v?/service.proto"))
+	require.True(t, fs.IsGlob("api/[ab].proto"))
+	require.False(t, fs.IsGlob("api/v1/service.proto"))
+}
+
+func TestGlob(t *testing.T) {
+	t.Parallel()
+
+	afs := afero.NewMemMapFs()
+
+	for _, name := range []string{
+		"root/a.proto",
+		"root/b.txt",
+		"root/api/v1/service.proto",
+		"root/api/v1/types.proto",
+		"root/api/v2/service.proto",
+		"root/third_party/google/api/annotations.proto",
+	} {
+		require.NoError(t, afero.WriteFile(afs, name, nil, 0o644))
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{
+			name:    "all files recursively",
+			pattern: "**/*.proto",
+			want: []string{
+				"a.proto",
+				"api/v1/service.proto",
+				"api/v1/types.proto",
+				"api/v2/service.proto",
+				"third_party/google/api/annotations.proto",
+			},
+		},
+		{
+			name:    "top level files",
+			pattern: "*.proto",
+			want:    []string{"a.proto"},
+		},
+		{
+			name:    "single directory wildcard",
+			pattern: "api/*/service.proto",
+			want:    []string{"api/v1/service.proto", "api/v2/service.proto"},
+		},
+		{
+			name:    "any directories in the middle",
+			pattern: "api/**/types.proto",
+			want:    []string{"api/v1/types.proto"},
+		},
+		{
+			name:    "no matches",
+			pattern: "**/*.yaml",
+			want:    []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := fs.Glob(afs, "root", tt.pattern)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGlobInvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	_, err := fs.Glob(afero.NewMemMapFs(), ".", "[")
+	require.Error(t, err)
+}
@@ -5,10 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/heartandu/easyrpc/pkg/descriptor"
 	"github.com/heartandu/easyrpc/pkg/format"
@@ -16,30 +23,47 @@ import (
 
 // Call represents a use case for making RPC calls.
 type Call struct {
-	output io.Writer
-	ds     descriptor.Source
-	cc     grpc.ClientConnInterface
-	mp     format.MessageParser
-	mf     format.MessageFormatter
-	md     metadata.MD
+	output     io.Writer
+	verboseOut io.Writer
+	ds         descriptor.Source
+	cc         grpc.ClientConnInterface
+	mp         format.MessageParser
+	mf         format.MessageFormatter
+	md         metadata.MD
+	timeout    time.Duration
+
+	mu          sync.Mutex
+	interrupted chan struct{}
+	cancel      context.CancelFunc
+	halfClose   bool
+
+	sendMu     sync.Mutex
+	sendClosed bool
 }
 
 // NewCall returns a new instance of Call.
+// Response headers, trailers and the final status are written to verboseOutput, use io.Discard to omit them.
+// If timeout is positive, it's used as the call deadline.
 func NewCall(
 	output io.Writer,
+	verboseOutput io.Writer,
 	descSrc descriptor.Source,
 	clientConn grpc.ClientConnInterface,
 	msgParser format.MessageParser,
 	msgFormatter format.MessageFormatter,
 	md metadata.MD,
+	timeout time.Duration,
 ) *Call {
 	return &Call{
-		output: output,
-		ds:     descSrc,
-		cc:     clientConn,
-		mp:     msgParser,
-		mf:     msgFormatter,
-		md:     md,
+		output:      output,
+		verboseOut:  verboseOutput,
+		ds:          descSrc,
+		cc:          clientConn,
+		mp:          msgParser,
+		mf:          msgFormatter,
+		md:          md,
+		timeout:     timeout,
+		interrupted: make(chan struct{}),
 	}
 }
 
@@ -50,13 +74,85 @@ func (c *Call) MakeRPCCall(ctx context.Context, methodName string) error {
 		return fmt.Errorf("failed to find method %q: %w", methodName, err)
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c.setCancel(cancel, m.IsStreamingClient())
+
+	parent := ctx
+
+	if c.timeout > 0 {
+		var cancelTimeout context.CancelFunc
+
+		ctx, cancelTimeout = context.WithTimeout(ctx, c.timeout)
+		defer cancelTimeout()
+	}
+
 	ctx = metadata.NewOutgoingContext(ctx, c.md)
 
 	if m.IsStreamingClient() || m.IsStreamingServer() {
-		return c.streamCall(ctx, m)
+		err = c.streamCall(ctx, m)
+	} else {
+		err = c.unaryCall(ctx, m)
+	}
+
+	// The timeout is only mentioned if it has expired, rather than a deadline of the parent context.
+	if err != nil && c.timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) && parent.Err() == nil {
+		return fmt.Errorf("call didn't complete within %s: %w", c.timeout, err)
+	}
+
+	return err
+}
+
+// Interrupt interrupts the ongoing call. It's safe to call it concurrently with MakeRPCCall.
+// The first interrupt of a client streaming call stops sending request messages and half-closes the stream,
+// so the final response and status can still be received. Other calls are cancelled, as well as
+// a client streaming call interrupted for the second time.
+func (c *Call) Interrupt() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-c.interrupted:
+	default:
+		close(c.interrupted)
+
+		if c.halfClose {
+			return
+		}
+	}
+
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// cancelCall cancels the ongoing call.
+func (c *Call) cancelCall() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
 	}
+}
+
+// setCancel stores the cancel function of the call and cancels the call right away,
+// if it has been interrupted before it started.
+func (c *Call) setCancel(cancel context.CancelFunc, halfClose bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	return c.unaryCall(ctx, m)
+	c.cancel = cancel
+	c.halfClose = halfClose
+
+	select {
+	case <-c.interrupted:
+		if !halfClose {
+			cancel()
+		}
+	default:
+	}
 }
 
 func (c *Call) streamCall(ctx context.Context, m descriptor.Method) error {
@@ -70,16 +166,75 @@ func (c *Call) streamCall(ctx context.Context, m descriptor.Method) error {
 		return fmt.Errorf("failed to create stream: %w", err)
 	}
 
-	if err := c.streamRequestMessages(stream, m); err != nil {
-		return fmt.Errorf("failed to stream request messages: %w", err)
+	err = c.exchangeStreamMessages(ctx, stream, m)
+
+	// Header blocks until headers are received or the stream is done, so it's safe to call it at the very end.
+	header, _ := stream.Header() //nolint:errcheck // The error is reported by the stream itself.
+	c.printVerbose(header, stream.Trailer(), err)
+
+	return err
+}
+
+// exchangeStreamMessages sends request messages and receives response messages concurrently,
+// so responses are printed as soon as they arrive, regardless of how many requests are sent.
+func (c *Call) exchangeStreamMessages(ctx context.Context, stream grpc.ClientStream, m descriptor.Method) error {
+	sendErr := make(chan error, 1)
+
+	go func() {
+		err := c.sendStream(ctx, stream, m)
+		sendErr <- err
+
+		// Failed request must end the call, otherwise the server may wait for more messages forever.
+		if err != nil {
+			c.cancelCall()
+		}
+	}()
+
+	recvErr := c.streamResponseMessages(stream, m)
+
+	// The server may end the call before all request messages are sent, so the sending side is closed
+	// from here as well, and the remaining requests are never sent.
+	if err := c.closeSend(stream); err != nil && recvErr == nil {
+		recvErr = fmt.Errorf("failed to close stream: %w", err)
 	}
 
-	if err := stream.CloseSend(); err != nil {
-		return fmt.Errorf("failed to close stream: %w", err)
+	select {
+	case err := <-sendErr:
+		if err != nil {
+			return err
+		}
+	default:
+	}
+
+	if recvErr != nil {
+		return fmt.Errorf("failed to stream response messages: %w", recvErr)
+	}
+
+	return nil
+}
+
+// sendStream sends request messages until the input is exhausted, the call is interrupted or done,
+// and then half-closes the stream.
+func (c *Call) sendStream(ctx context.Context, stream grpc.ClientStream, m descriptor.Method) error {
+	reqErr := make(chan error, 1)
+
+	// Reading request messages may block indefinitely, e.g. when they are typed in stdin,
+	// so it's done in a separate goroutine, which is abandoned once the call is interrupted or done.
+	go func() {
+		reqErr <- c.streamRequestMessages(stream, m)
+	}()
+
+	select {
+	case err := <-reqErr:
+		if err != nil {
+			return fmt.Errorf("failed to stream request messages: %w", err)
+		}
+	case <-c.interrupted:
+	case <-ctx.Done():
 	}
 
-	if err := c.streamResponseMessages(stream, m); err != nil {
-		return fmt.Errorf("failed to stream response messages: %w", err)
+	if err := c.closeSend(stream); err != nil {
+		return fmt.Errorf("failed to close stream: %w", err)
 	}
 
 	return nil
@@ -87,7 +242,7 @@ func (c *Call) streamCall(ctx context.Context, m descriptor.Method) error {
 
 func (c *Call) unaryCall(ctx context.Context, m descriptor.Method) error {
 	req, resp := m.RequestMessage(), m.ResponseMessage()
-	if err := c.mp.Next(req); err != nil && !errors.Is(err, io.EOF) {
+	if err := c.nextRequest(ctx, req); err != nil && !errors.Is(err, io.EOF) {
 		return fmt.Errorf("failed to make request: %w", err)
 	}
 
@@ -96,7 +251,12 @@ func (c *Call) unaryCall(ctx context.Context, m descriptor.Method) error {
 		return fmt.Errorf("failed to convert method name: %w", err)
 	}
 
-	if err = c.cc.Invoke(ctx, method, req, resp); err != nil {
+	var header, trailer metadata.MD
+
+	err = c.cc.Invoke(ctx, method, req, resp, grpc.Header(&header), grpc.Trailer(&trailer))
+	c.printVerbose(header, trailer, err)
+
+	if err != nil {
 		return fmt.Errorf("failed to invoke rpc: %w", err)
 	}
 
@@ -107,6 +267,22 @@ func (c *Call) unaryCall(ctx context.Context, m descriptor.Method) error {
 	return nil
 }
 
+// nextRequest parses the next request message, giving up if the call is done while waiting for the input.
+func (c *Call) nextRequest(ctx context.Context, req proto.Message) error {
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- c.mp.Next(req)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err //nolint:wrapcheck // The error is wrapped by the caller.
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
 func (c *Call) streamRequestMessages(stream grpc.ClientStream, m descriptor.Method) error {
 	for {
 		req := m.RequestMessage()
@@ -118,12 +294,43 @@ func (c *Call) streamRequestMessages(stream grpc.ClientStream, m descriptor.Meth
 			return fmt.Errorf("failed to make request: %w", err)
 		}
 
-		if err := stream.SendMsg(req); err != nil {
+		if err := c.sendMsg(stream, req); err != nil {
+			// The stream has been terminated, the actual status is received along with the response.
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
 			return fmt.Errorf("failed to send message: %w", err)
 		}
 	}
 }
 
+// sendMsg sends the message unless the sending side of the stream is already closed.
+func (c *Call) sendMsg(stream grpc.ClientStream, msg proto.Message) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if c.sendClosed {
+		return io.EOF
+	}
+
+	return stream.SendMsg(msg) //nolint:wrapcheck // The error is wrapped by the caller.
+}
+
+// closeSend closes the sending side of the stream, so no more messages are sent after that.
+func (c *Call) closeSend(stream grpc.ClientStream) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if c.sendClosed {
+		return nil
+	}
+
+	c.sendClosed = true
+
+	return stream.CloseSend() //nolint:wrapcheck // The error is wrapped by the caller.
+}
+
 func (c *Call) streamResponseMessages(stream grpc.ClientStream, m descriptor.Method) error {
 	for {
 		resp := m.ResponseMessage()
@@ -142,12 +349,108 @@ func (c *Call) streamResponseMessages(stream grpc.ClientStream, m descriptor.Met
 }
 
 func (c *Call) printResponse(resp proto.Message) error {
+	resp, err := c.resolveExtensions(resp)
+	if err != nil {
+		return err
+	}
+
 	formattedResp, err := c.mf.Format(resp)
 	if err != nil {
 		return fmt.Errorf("failed to format response: %w", err)
 	}
 
-	fmt.Fprintf(c.output, "%v\n", formattedResp)
+	fmt.Fprintf(c.output, "%s%s", formattedResp, format.Delimiter(c.mf))
 
 	return nil
 }
+
+// resolveExtensions decodes the response once again resolving extension types with the descriptor source.
+// The gRPC codec only knows about globally registered extensions, so the rest of them are left as unknown fields.
+func (c *Call) resolveExtensions(resp proto.Message) (proto.Message, error) {
+	if !hasUnknownFields(resp.ProtoReflect()) {
+		return resp, nil
+	}
+
+	b, err := proto.MarshalOptions{AllowPartial: true}.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	resolved := resp.ProtoReflect().New().Interface()
+
+	err = proto.UnmarshalOptions{AllowPartial: true, Resolver: descriptor.NewTypeResolver(c.ds)}.Unmarshal(b, resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return resolved, nil
+}
+
+// hasUnknownFields reports whether the message or any of its nested messages has unknown fields.
+func hasUnknownFields(m protoreflect.Message) bool {
+	if len(m.GetUnknown()) > 0 {
+		return true
+	}
+
+	found := false
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len() && !found; i++ {
+				found = hasUnknownFields(list.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				found = hasUnknownFields(v.Message())
+				return !found
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			found = hasUnknownFields(v.Message())
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// printVerbose writes response headers, trailers and the status of a successful call to the verbose output.
+// The status of a failed call is reported along with the returned error.
+func (c *Call) printVerbose(header, trailer metadata.MD, err error) {
+	fmt.Fprintln(c.verboseOut, "Response headers:")
+	printMetadata(c.verboseOut, header)
+
+	fmt.Fprintln(c.verboseOut, "Response trailers:")
+	printMetadata(c.verboseOut, trailer)
+
+	if err == nil {
+		fmt.Fprintf(c.verboseOut, "Status code: %s\n", codes.OK)
+	}
+}
+
+// printMetadata writes metadata to the output sorted by keys, one value per line.
+func printMetadata(w io.Writer, md metadata.MD) {
+	if len(md) == 0 {
+		fmt.Fprintln(w, "  (empty)")
+		return
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(md)) {
+		for _, value := range md[key] {
+			fmt.Fprintf(w, "  %s: %s\n", key, value)
+		}
+	}
+}
+
+// RPCStatus returns the gRPC status carried by the error, looking through any wrapping errors.
+// If the error doesn't carry a status, false is returned along with the status of an unknown error.
+func RPCStatus(err error) (*status.Status, bool) {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		return grpcErr.GRPCStatus(), true
+	}
+
+	return status.Convert(err), false
+}
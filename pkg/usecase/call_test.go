@@ -0,0 +1,101 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+	"github.com/heartandu/easyrpc/pkg/format"
+	"github.com/heartandu/easyrpc/pkg/usecase"
+)
+
+// hangingConn is a client connection, which calls never complete until their context is done.
+type hangingConn struct{}
+
+func (hangingConn) Invoke(ctx context.Context, _ string, _, _ any, _ ...grpc.CallOption) error {
+	<-ctx.Done()
+
+	return status.FromContextError(ctx.Err()).Err()
+}
+
+func (hangingConn) NewStream(
+	ctx context.Context,
+	_ *grpc.StreamDesc,
+	_ string,
+	_ ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	<-ctx.Done()
+
+	return nil, status.FromContextError(ctx.Err()).Err()
+}
+
+func TestCallDeadline(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "echo.proto", []byte(`syntax = "proto3";
+package echo;
+service EchoService { rpc Echo(EchoRequest) returns (EchoRequest); }
+message EchoRequest { string msg = 1; }
+`), 0o644))
+
+	ds, err := descriptor.ProtoFilesSource(context.Background(), fs, nil, []string{"echo.proto"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		timeout     time.Duration
+		parent      time.Duration
+		wantTimeout bool
+	}{
+		{
+			name:        "call timeout",
+			timeout:     10 * time.Millisecond,
+			parent:      time.Minute,
+			wantTimeout: true,
+		},
+		{
+			name:    "parent deadline without timeout",
+			parent:  10 * time.Millisecond,
+			timeout: 0,
+		},
+		{
+			name:    "parent deadline before timeout",
+			parent:  10 * time.Millisecond,
+			timeout: time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.parent)
+			defer cancel()
+
+			mp := format.JSONMessageParser(strings.NewReader("{}"), protojson.UnmarshalOptions{})
+			mf := format.JSONMessageFormatter(protojson.MarshalOptions{})
+			call := usecase.NewCall(&bytes.Buffer{}, io.Discard, ds, hangingConn{}, mp, mf, metadata.MD{}, tt.timeout)
+
+			err := call.MakeRPCCall(ctx, "echo.EchoService.Echo")
+			require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+			if tt.wantTimeout {
+				require.ErrorContains(t, err, "call didn't complete within "+tt.timeout.String())
+			} else {
+				require.NotContains(t, err.Error(), "didn't complete within")
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoprint"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+)
+
+// Describe represents a use case for printing definitions of protobuf symbols.
+type Describe struct {
+	out io.Writer
+	ds  descriptor.Source
+}
+
+// NewDescribe returns a new instance of Describe.
+func NewDescribe(out io.Writer, ds descriptor.Source) *Describe {
+	return &Describe{
+		out: out,
+		ds:  ds,
+	}
+}
+
+// Describe prints the definition of the symbol in the proto syntax, including comments and options if available.
+// The symbol name is relative to the package, unless it's a fully qualified name of a known symbol.
+func (d *Describe) Describe(pkg, symbol string) error {
+	sd, err := d.findSymbol(pkg, symbol)
+	if err != nil {
+		return err
+	}
+
+	wrapped, err := desc.WrapDescriptor(sd)
+	if err != nil {
+		return fmt.Errorf("failed to wrap descriptor of %q: %w", sd.FullName(), err)
+	}
+
+	printer := protoprint.Printer{Indent: "  "}
+
+	definition, err := printer.PrintProtoToString(wrapped)
+	if err != nil {
+		return fmt.Errorf("failed to print definition of %q: %w", sd.FullName(), err)
+	}
+
+	fmt.Fprint(d.out, definition)
+
+	return nil
+}
+
+func (d *Describe) findSymbol(pkg, symbol string) (protoreflect.Descriptor, error) {
+	candidates := []string{symbol}
+	if pkg != "" {
+		candidates = []string{pkg + symbolDelim + symbol, symbol}
+	}
+
+	for _, candidate := range candidates {
+		sd, err := d.ds.FindSymbol(candidate)
+		if err == nil {
+			return sd, nil
+		}
+
+		// Other errors, e.g. of an unavailable server, aren't hidden, so they keep their status.
+		if !errors.Is(err, descriptor.ErrSymbolNotFound) {
+			return nil, fmt.Errorf("failed to find symbol %q: %w", candidate, err)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", descriptor.ErrSymbolNotFound, symbol)
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoprint"
+	"github.com/spf13/afero"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+)
+
+// exportDirPerm is the permission of directories created for exported proto files.
+const exportDirPerm = 0o755
+
+// ErrInvalidFileName is returned when a file name of the source would be written outside of the export directory.
+var ErrInvalidFileName = errors.New("invalid file name")
+
+// Export represents a use case for saving descriptors of a descriptor source for offline usage.
+type Export struct {
+	ds descriptor.Source
+}
+
+// NewExport returns a new instance of Export.
+func NewExport(ds descriptor.Source) *Export {
+	return &Export{
+		ds: ds,
+	}
+}
+
+// Protoset writes all files of the source services along with their imports as a serialized FileDescriptorSet.
+func (e *Export) Protoset(out io.Writer) error {
+	set, err := descriptor.NewFileDescriptorSet(e.ds)
+	if err != nil {
+		return fmt.Errorf("failed to collect files: %w", err)
+	}
+
+	b, err := proto.Marshal(set)
+	if err != nil {
+		return fmt.Errorf("failed to marshal file descriptor set: %w", err)
+	}
+
+	if _, err := out.Write(b); err != nil {
+		return fmt.Errorf("failed to write file descriptor set: %w", err)
+	}
+
+	return nil
+}
+
+// ProtoFiles writes all files of the source services along with their imports as .proto files
+// into the directory, keeping their import paths, so the directory can be used as an import path.
+func (e *Export) ProtoFiles(fs afero.Fs, dir string) error {
+	files, err := descriptor.ListFiles(e.ds)
+	if err != nil {
+		return fmt.Errorf("failed to list files: %w", err)
+	}
+
+	wrapped := make([]*desc.FileDescriptor, 0, len(files))
+
+	for _, fd := range files {
+		// File names come from the source, e.g. a reflection server, so they must not escape the directory.
+		if !filepath.IsLocal(filepath.FromSlash(fd.Path())) {
+			return fmt.Errorf("%w: %q", ErrInvalidFileName, fd.Path())
+		}
+
+		file, err := desc.WrapFile(fd)
+		if err != nil {
+			return fmt.Errorf("failed to wrap file %q: %w", fd.Path(), err)
+		}
+
+		wrapped = append(wrapped, file)
+	}
+
+	printer := protoprint.Printer{Indent: "  "}
+
+	err = printer.PrintProtoFiles(wrapped, func(name string) (io.WriteCloser, error) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := fs.MkdirAll(filepath.Dir(path), exportDirPerm); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+
+		return fs.Create(path) //nolint:wrapcheck // The printer adds the file name to the error.
+	})
+	if err != nil {
+		return fmt.Errorf("failed to print proto files: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+	"github.com/heartandu/easyrpc/pkg/usecase"
+)
+
+func TestExportProtoFiles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		fileName string
+		wantPath string
+		wantErr  error
+	}{
+		{
+			name:     "nested file",
+			fileName: "echo/v1/echo.proto",
+			wantPath: "/export/out/echo/v1/echo.proto",
+		},
+		{
+			name:     "parent directory",
+			fileName: "../../evil/echo.proto",
+			wantErr:  usecase.ErrInvalidFileName,
+		},
+		{
+			name:     "absolute path",
+			fileName: "/evil/echo.proto",
+			wantErr:  usecase.ErrInvalidFileName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ds, err := descriptor.FileDescriptorSetSource(&descriptorpb.FileDescriptorSet{
+				File: []*descriptorpb.FileDescriptorProto{{
+					Name:    proto.String(tt.fileName),
+					Package: proto.String("echo"),
+					Syntax:  proto.String("proto3"),
+					Service: []*descriptorpb.ServiceDescriptorProto{{Name: proto.String("EchoService")}},
+				}},
+			})
+			require.NoError(t, err)
+
+			fs := afero.NewMemMapFs()
+
+			err = usecase.NewExport(ds).ProtoFiles(fs, "/export/out")
+			require.ErrorIs(t, err, tt.wantErr)
+
+			// Nothing is written if any of the files would escape the directory.
+			for _, path := range []string{"/export/out", "/export/evil/echo.proto", "/evil/echo.proto"} {
+				exists, err := afero.Exists(fs, path)
+				require.NoError(t, err)
+				require.Equal(t, tt.wantErr == nil && path == "/export/out", exists, path)
+			}
+
+			if tt.wantPath != "" {
+				exists, err := afero.Exists(fs, tt.wantPath)
+				require.NoError(t, err)
+				require.True(t, exists)
+			}
+		})
+	}
+}
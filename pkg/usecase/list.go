@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+)
+
+var (
+	// ErrServiceNotFound is returned when the requested service is not provided by the descriptor source.
+	ErrServiceNotFound = errors.New("service not found")
+	// ErrNotAService is returned when the requested symbol is not a service.
+	ErrNotAService = errors.New("selected element is not a service")
+)
+
+// List represents a use case for listing services and methods of a descriptor source.
+type List struct {
+	out  io.Writer
+	ds   descriptor.Source
+	long bool
+}
+
+// NewList returns a new instance of List.
+// In the long format, methods are printed with their request and response types and streaming kind.
+func NewList(out io.Writer, ds descriptor.Source, long bool) *List {
+	return &List{
+		out:  out,
+		ds:   ds,
+		long: long,
+	}
+}
+
+// Services prints all services of the package, or all services if the package is empty.
+// In the long format, every service is followed by its methods.
+func (l *List) Services(pkg string) error {
+	services, err := l.ds.ListServices()
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	for _, service := range services {
+		if pkg != "" && servicePackage(service) != pkg {
+			continue
+		}
+
+		fmt.Fprintln(l.out, service)
+
+		if !l.long {
+			continue
+		}
+
+		sd, err := l.findService(service)
+		if err != nil {
+			return err
+		}
+
+		l.printMethods(sd, "  ")
+	}
+
+	return nil
+}
+
+// Methods prints all methods of the service.
+// The service name is relative to the package, unless it's a fully qualified name of a known service.
+func (l *List) Methods(pkg, service string) error {
+	name, err := l.resolveService(pkg, service)
+	if err != nil {
+		return err
+	}
+
+	sd, err := l.findService(name)
+	if err != nil {
+		return err
+	}
+
+	l.printMethods(sd, "")
+
+	return nil
+}
+
+// resolveService returns a fully qualified name of the service.
+func (l *List) resolveService(pkg, service string) (string, error) {
+	services, err := l.ds.ListServices()
+	if err != nil {
+		return "", fmt.Errorf("failed to list services: %w", err)
+	}
+
+	candidates := []string{service}
+	if pkg != "" {
+		candidates = []string{pkg + symbolDelim + service, service}
+	}
+
+	for _, candidate := range candidates {
+		if slices.Contains(services, candidate) {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrServiceNotFound, service)
+}
+
+func (l *List) findService(name string) (protoreflect.ServiceDescriptor, error) {
+	d, err := l.ds.FindSymbol(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find service %q: %w", name, err)
+	}
+
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNotAService, name)
+	}
+
+	return sd, nil
+}
+
+func (l *List) printMethods(sd protoreflect.ServiceDescriptor, indent string) {
+	methods := sd.Methods()
+
+	for i := range methods.Len() {
+		md := methods.Get(i)
+
+		if !l.long {
+			fmt.Fprintf(l.out, "%s%s\n", indent, md.FullName())
+			continue
+		}
+
+		fmt.Fprintf(
+			l.out,
+			"%srpc %s(%s) returns (%s)\n",
+			indent,
+			md.Name(),
+			streamType(md.Input(), md.IsStreamingClient()),
+			streamType(md.Output(), md.IsStreamingServer()),
+		)
+	}
+}
+
+// streamType returns the message name in the proto syntax of a method argument.
+func streamType(md protoreflect.MessageDescriptor, streaming bool) string {
+	if streaming {
+		return "stream " + string(md.FullName())
+	}
+
+	return string(md.FullName())
+}
+
+// servicePackage returns the package of the fully qualified service name.
+func servicePackage(service string) string {
+	if i := strings.LastIndex(service, symbolDelim); i >= 0 {
+		return service[:i]
+	}
+
+	return ""
+}
@@ -0,0 +1,375 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+	"github.com/heartandu/easyrpc/pkg/format"
+	"github.com/heartandu/easyrpc/pkg/fqn"
+)
+
+const (
+	replCmdCall    = "call"
+	replCmdList    = "list"
+	replCmdPackage = "package"
+	replCmdService = "service"
+	replCmdHelp    = "help"
+	replCmdExit    = "exit"
+	replCmdQuit    = "quit"
+
+	symbolDelim = "."
+)
+
+var (
+	// ErrUnknownCommand is returned when the REPL receives an unsupported command.
+	ErrUnknownCommand = errors.New("unknown command")
+	// ErrMissingMethod is returned when the method name is not provided to the call command.
+	ErrMissingMethod = errors.New("missing method name")
+)
+
+const replHelp = `Available commands:
+  call <method> [data]  call a method, if data is omitted, an editable request body is prompted
+  list                  list methods of the current package and service
+  package [name]        set the default package, or reset it if the name is omitted
+  service [name]        set the default service, or reset it if the name is omitted
+  help                  print this help
+  exit, quit            leave the REPL`
+
+// LineReader is an interface for reading user input line by line.
+type LineReader interface {
+	// Readline reads a line of user input.
+	Readline() (string, error)
+	// ReadlineWithDefault reads a line of user input with a pre-filled editable value.
+	ReadlineWithDefault(what string) (string, error)
+	// SetPrompt changes the prompt shown to the user.
+	SetPrompt(prompt string)
+}
+
+// ParserFunc creates a new MessageParser reading from the given input.
+type ParserFunc func(input io.Reader) format.MessageParser
+
+// Repl represents a use case for an interactive session over a single connection and descriptor source.
+type Repl struct {
+	output    io.Writer
+	lr        LineReader
+	ds        descriptor.Source
+	cc        grpc.ClientConnInterface
+	newParser ParserFunc
+	mf        format.MessageFormatter
+	sf        format.StatusFormatter
+	md        metadata.MD
+	timeout   time.Duration
+
+	pkg     string
+	svc     string
+	methods []string
+
+	mu      sync.Mutex
+	curCall *Call
+}
+
+// NewRepl returns a new instance of Repl.
+func NewRepl(
+	output io.Writer,
+	lineReader LineReader,
+	descSrc descriptor.Source,
+	clientConn grpc.ClientConnInterface,
+	newParser ParserFunc,
+	msgFormatter format.MessageFormatter,
+	statusFormatter format.StatusFormatter,
+	md metadata.MD,
+	timeout time.Duration,
+	pkg, svc string,
+) *Repl {
+	return &Repl{
+		output:    output,
+		lr:        lineReader,
+		ds:        descSrc,
+		cc:        clientConn,
+		newParser: newParser,
+		mf:        msgFormatter,
+		sf:        statusFormatter,
+		md:        md,
+		timeout:   timeout,
+		pkg:       pkg,
+		svc:       svc,
+	}
+}
+
+// Run reads and executes commands until the input is exhausted or the user exits.
+func (r *Repl) Run(ctx context.Context) error {
+	r.updatePrompt()
+
+	for {
+		line, err := r.lr.Readline()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("failed to read line: %w", err)
+		}
+
+		name, args := splitCommand(line)
+
+		if name == replCmdExit || name == replCmdQuit {
+			return nil
+		}
+
+		if err := r.exec(ctx, name, args); err != nil {
+			fmt.Fprintf(r.output, "Error: %v\n", err)
+		}
+	}
+}
+
+// Interrupt interrupts the ongoing call and returns true, or returns false if there is no ongoing call.
+// It's safe to call it concurrently with Run.
+func (r *Repl) Interrupt() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.curCall == nil {
+		return false
+	}
+
+	r.curCall.Interrupt()
+
+	return true
+}
+
+// CompleteMethod returns method names relative to the current package and service.
+func (r *Repl) CompleteMethod(_ string) []string {
+	methods, err := r.listMethods()
+	if err != nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(methods))
+
+	for _, method := range methods {
+		if name := r.relativeMethodName(method); name != "" {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// CompletePackage returns all known package names.
+func (r *Repl) CompletePackage(_ string) []string {
+	return r.uniqueSymbols(func(pkg, _ string) string { return pkg })
+}
+
+// CompleteService returns service names relative to the current package.
+func (r *Repl) CompleteService(_ string) []string {
+	return r.uniqueSymbols(func(pkg, svc string) string {
+		if r.pkg == "" {
+			return pkg + symbolDelim + svc
+		}
+
+		if pkg != r.pkg {
+			return ""
+		}
+
+		return svc
+	})
+}
+
+func (r *Repl) exec(ctx context.Context, name, args string) error {
+	switch name {
+	case "":
+		return nil
+	case replCmdHelp:
+		fmt.Fprintln(r.output, replHelp)
+	case replCmdPackage:
+		r.pkg = args
+		r.updatePrompt()
+	case replCmdService:
+		r.svc = args
+		r.updatePrompt()
+	case replCmdList:
+		for _, method := range r.CompleteMethod("") {
+			fmt.Fprintln(r.output, method)
+		}
+	case replCmdCall:
+		return r.call(ctx, args)
+	default:
+		return fmt.Errorf("%w %q, type %q for the list of commands", ErrUnknownCommand, name, replCmdHelp)
+	}
+
+	return nil
+}
+
+func (r *Repl) call(ctx context.Context, args string) error {
+	methodName, data := splitCommand(args)
+	if methodName == "" {
+		return ErrMissingMethod
+	}
+
+	methodName = fqn.FullyQualifiedMethodName(methodName, r.pkg, r.svc)
+
+	if data == "" {
+		m, err := r.ds.FindMethod(methodName)
+		if err != nil {
+			return fmt.Errorf("failed to find method %q: %w", methodName, err)
+		}
+
+		skeleton := format.JSONMessageFormatter(protojson.MarshalOptions{EmitUnpopulated: true})
+
+		body, err := skeleton.Format(m.RequestMessage())
+		if err != nil {
+			return fmt.Errorf("failed to format request: %w", err)
+		}
+
+		r.lr.SetPrompt("data> ")
+		data, err = r.lr.ReadlineWithDefault(body)
+		r.updatePrompt()
+
+		if err != nil {
+			return fmt.Errorf("failed to read request data: %w", err)
+		}
+	}
+
+	call := NewCall(r.output, io.Discard, r.ds, r.cc, r.newParser(strings.NewReader(data)), r.mf, r.md, r.timeout)
+
+	r.setCurrentCall(call)
+	defer r.setCurrentCall(nil)
+
+	if err := call.MakeRPCCall(ctx, methodName); err != nil {
+		r.printStatus(err)
+
+		return fmt.Errorf("call rpc failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repl) setCurrentCall(call *Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.curCall = call
+}
+
+// printStatus writes the formatted gRPC status if the error carries one.
+func (r *Repl) printStatus(err error) {
+	st, ok := RPCStatus(err)
+	if !ok {
+		return
+	}
+
+	if formattedStatus, err := r.sf.Format(st); err == nil {
+		fmt.Fprint(r.output, formattedStatus)
+	}
+}
+
+// listMethods returns all methods of the descriptor source, querying it only once per session.
+func (r *Repl) listMethods() ([]string, error) {
+	if r.methods != nil {
+		return r.methods, nil
+	}
+
+	methods, err := r.ds.ListMethods()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list methods: %w", err)
+	}
+
+	r.methods = methods
+
+	return methods, nil
+}
+
+// relativeMethodName shortens the fully qualified method name according to the current package and service.
+// Empty string is returned if the method doesn't belong to them.
+func (r *Repl) relativeMethodName(method string) string {
+	pkg, svc, name, ok := splitMethod(method)
+	if !ok {
+		return ""
+	}
+
+	if r.pkg != "" && pkg != r.pkg {
+		return ""
+	}
+
+	if r.svc != "" {
+		if r.svc != svc && r.svc != pkg+symbolDelim+svc {
+			return ""
+		}
+
+		return name
+	}
+
+	if r.pkg != "" {
+		return svc + symbolDelim + name
+	}
+
+	return method
+}
+
+func (r *Repl) uniqueSymbols(mapFunc func(pkg, svc string) string) []string {
+	methods, err := r.listMethods()
+	if err != nil {
+		return nil
+	}
+
+	encountered := map[string]struct{}{}
+	result := make([]string, 0)
+
+	for _, method := range methods {
+		pkg, svc, _, ok := splitMethod(method)
+		if !ok {
+			continue
+		}
+
+		symbol := mapFunc(pkg, svc)
+		if symbol == "" {
+			continue
+		}
+
+		if _, ok := encountered[symbol]; !ok {
+			result = append(result, symbol)
+			encountered[symbol] = struct{}{}
+		}
+	}
+
+	return result
+}
+
+func (r *Repl) updatePrompt() {
+	scope := strings.Trim(strings.Join([]string{r.pkg, r.svc}, symbolDelim), symbolDelim)
+	if scope == "" {
+		r.lr.SetPrompt("easyrpc> ")
+		return
+	}
+
+	r.lr.SetPrompt(fmt.Sprintf("easyrpc %s> ", scope))
+}
+
+// splitCommand splits the line into the first word and the rest of the line.
+func splitCommand(line string) (string, string) {
+	name, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+
+	return name, strings.TrimSpace(rest)
+}
+
+// splitMethod splits a fully qualified method name into package, service and method names.
+func splitMethod(method string) (string, string, string, bool) {
+	const minParts = 3
+
+	parts := strings.Split(method, symbolDelim)
+	if len(parts) < minParts {
+		return "", "", "", false
+	}
+
+	return strings.Join(parts[:len(parts)-2], symbolDelim), parts[len(parts)-2], parts[len(parts)-1], true
+}
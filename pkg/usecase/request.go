@@ -1,24 +1,38 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 
 	"github.com/spf13/afero"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/heartandu/easyrpc/pkg/descriptor"
 	"github.com/heartandu/easyrpc/pkg/editor"
 	"github.com/heartandu/easyrpc/pkg/format"
 )
 
+// ErrEmptyRequest is returned when the edited request is empty, which aborts editing.
+var ErrEmptyRequest = errors.New("request is empty")
+
+const (
+	// invalidRequestHeader is the first line of a header shown above an invalid request,
+	// when the editor is re-opened.
+	invalidRequestHeader = "The request is invalid, fix it or clear the file to abort:"
+	// jsonCommentPrefix starts line comments of request skeletons, which are always JSON.
+	jsonCommentPrefix = "//"
+)
+
 // Request represents a use case for populating a request message to stdout or a file.
 type Request struct {
-	out    io.Writer
-	editor editor.Editor
-	fs     afero.Fs
-	ds     descriptor.Source
-	mf     format.MessageFormatter
+	out       io.Writer
+	editor    editor.Editor
+	fs        afero.Fs
+	ds        descriptor.Source
+	newParser ParserFunc
+	opts      format.SkeletonOptions
 }
 
 // NewRequest returns a new instance of Request.
@@ -27,38 +41,110 @@ func NewRequest(
 	e editor.Editor,
 	fs afero.Fs,
 	ds descriptor.Source,
-	mf format.MessageFormatter,
+	newParser ParserFunc,
+	opts format.SkeletonOptions,
 ) *Request {
 	return &Request{
-		out:    out,
-		editor: e,
-		fs:     fs,
-		ds:     ds,
-		mf:     mf,
+		out:       out,
+		editor:    e,
+		fs:        fs,
+		ds:        ds,
+		newParser: newParser,
+		opts:      opts,
 	}
 }
 
-// Prepare formats a request message for the specified method,
+// Prepare generates a request skeleton for the specified method,
 // and optionally allows editing it before writing it to an output.
+// The skeleton is annotated with comments only in the editor, so the output is always plain JSON.
 func (r *Request) Prepare(method string) error {
 	m, err := r.ds.FindMethod(method)
 	if err != nil {
 		return fmt.Errorf("failed to find method: %w", err)
 	}
 
-	msg, err := r.mf.Format(m.RequestMessage())
-	if err != nil {
-		return fmt.Errorf("failed to format message: %w", err)
-	}
+	opts := r.opts
+	opts.Comments = r.editor != nil
+
+	msg := format.JSONSkeleton(m.RequestMessage().ProtoReflect().Descriptor(), opts)
 
 	if r.editor != nil {
-		msg, err = r.editor.Run(msg)
+		msg, err = EditRequest(r.editor, r.newParser, jsonCommentPrefix, m.RequestMessage(), msg)
 		if err != nil {
-			return fmt.Errorf("failed to edit the message: %w", err)
+			return err
 		}
+
+		msg = format.StripLineComments(msg)
 	}
 
 	fmt.Fprintf(r.out, "%v\n", strings.TrimSpace(msg))
 
 	return nil
 }
+
+// EditRequest runs the editor until the edited request messages are valid. If they aren't, the editor is re-opened
+// with the user's text, which is preceded by a header describing the error. The header lines are commented
+// with commentPrefix, which must start line comments of the input format, so the header is skipped by the parser
+// even if the user changes it. The text is parsed with newParser, so it may contain a stream of messages.
+// ErrEmptyRequest is returned if the user clears the text.
+func EditRequest(
+	e editor.Editor,
+	newParser ParserFunc,
+	commentPrefix string,
+	req proto.Message,
+	msg string,
+) (string, error) {
+	header := ""
+
+	for {
+		edited, err := e.Run(header + msg)
+		if err != nil {
+			return "", fmt.Errorf("failed to edit the message: %w", err)
+		}
+
+		// The header is removed unless the user has changed it, which is fine, since it's a comment anyway.
+		msg = strings.TrimPrefix(edited, header)
+
+		err = validateRequest(newParser, req, msg)
+		if err == nil {
+			return msg, nil
+		}
+
+		if errors.Is(err, ErrEmptyRequest) {
+			return "", err
+		}
+
+		header = commentLines(commentPrefix, invalidRequestHeader+"\n"+err.Error()) + "\n"
+	}
+}
+
+// validateRequest parses every message of the input to check that it's a valid request.
+func validateRequest(newParser ParserFunc, req proto.Message, msg string) error {
+	parser := newParser(strings.NewReader(msg))
+
+	for i := 0; ; i++ {
+		err := parser.Next(req.ProtoReflect().New().Interface())
+		if errors.Is(err, io.EOF) {
+			if i == 0 {
+				return ErrEmptyRequest
+			}
+
+			return nil
+		}
+
+		if err != nil {
+			return err //nolint:wrapcheck // The error is shown to the user as is.
+		}
+	}
+}
+
+// commentLines turns every line of the text into a line comment starting with the prefix.
+func commentLines(prefix, text string) string {
+	var sb strings.Builder
+
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		sb.WriteString(prefix + " " + line + "\n")
+	}
+
+	return sb.String()
+}
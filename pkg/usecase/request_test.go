@@ -0,0 +1,198 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"github.com/heartandu/easyrpc/internal/testdata"
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+	"github.com/heartandu/easyrpc/pkg/format"
+	"github.com/heartandu/easyrpc/pkg/usecase"
+)
+
+var errAborted = errors.New("aborted")
+
+// fakeEditor returns the prepared edits one by one and records the messages it was opened with.
+type fakeEditor struct {
+	edits  []string
+	opened []string
+}
+
+func (e *fakeEditor) Run(msg string) (string, error) {
+	e.opened = append(e.opened, msg)
+
+	if len(e.edits) == 0 {
+		return "", errAborted
+	}
+
+	edit := e.edits[0]
+	e.edits = e.edits[1:]
+
+	return edit, nil
+}
+
+func TestRequestPrepareEdit(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "echo.proto", []byte(`syntax = "proto3";
+package echo;
+service EchoService { rpc Echo(EchoRequest) returns (EchoRequest); }
+message EchoRequest { string msg = 1; }
+`), 0o644))
+
+	ds, err := descriptor.ProtoFilesSource(context.Background(), fs, nil, []string{"echo.proto"})
+	require.NoError(t, err)
+
+	newParser := func(input io.Reader) format.MessageParser {
+		return format.JSONMessageParser(input, protojson.UnmarshalOptions{})
+	}
+
+	tests := []struct {
+		name       string
+		edits      []string
+		want       string
+		wantOpened []string
+		wantErr    error
+	}{
+		{
+			name:       "valid request",
+			edits:      []string{`{"msg": "hi"} // greeting`},
+			want:       "{\"msg\": \"hi\"}\n",
+			wantOpened: []string{"{\n  \"msg\": \"\"\n}"},
+		},
+		{
+			name:  "invalid request is re-opened",
+			edits: []string{`{"msg": 1}`, `{"msg": "1"}`},
+			want:  "{\"msg\": \"1\"}\n",
+			wantOpened: []string{
+				"{\n  \"msg\": \"\"\n}",
+				"// The request is invalid, fix it or clear the file to abort:\n" +
+					"// failed to unmarshal message: invalid input: proto",
+			},
+		},
+		{
+			name:    "empty request aborts",
+			edits:   []string{"// nothing\n"},
+			wantErr: usecase.ErrEmptyRequest,
+		},
+		{
+			name:    "editor error aborts",
+			edits:   []string{`{"msg": 1}`},
+			wantErr: errAborted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := &fakeEditor{edits: tt.edits}
+			out := &bytes.Buffer{}
+
+			err := usecase.NewRequest(out, e, fs, ds, newParser, format.SkeletonOptions{MaxDepth: 1}).
+				Prepare("echo.EchoService.Echo")
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, out.String())
+
+			if tt.wantOpened != nil {
+				// Error messages of protojson aren't stable, so only their beginnings are compared.
+				require.Len(t, e.opened, len(tt.wantOpened))
+
+				for i, want := range tt.wantOpened {
+					require.True(t, strings.HasPrefix(e.opened[i], want), e.opened[i])
+				}
+
+				// The user's text is kept below the header.
+				if i := len(e.opened) - 1; i > 0 {
+					require.True(t, strings.HasSuffix(e.opened[i], "\n\n"+tt.edits[i-1]), e.opened[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEditRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		newParser     usecase.ParserFunc
+		commentPrefix string
+		msg           string
+		edits         []string
+		want          string
+		wantHeader    string
+		wantErr       error
+	}{
+		{
+			name: "yaml",
+			newParser: func(input io.Reader) format.MessageParser {
+				return format.YAMLMessageParser(input, protojson.UnmarshalOptions{})
+			},
+			commentPrefix: "#",
+			msg:           "msg: hi\n",
+			edits:         []string{"msg: [hi]\n", "msg: hello\n"},
+			want:          "msg: hello\n",
+			wantHeader:    "# The request is invalid, fix it or clear the file to abort:\n# ",
+		},
+		{
+			name: "text",
+			newParser: func(input io.Reader) format.MessageParser {
+				return format.TextMessageParser(input, prototext.UnmarshalOptions{})
+			},
+			commentPrefix: "#",
+			msg:           "msg: \"hi\"\n",
+			edits:         []string{"msg: hi\n", "msg: \"hello\"\n"},
+			want:          "msg: \"hello\"\n",
+			wantHeader:    "# The request is invalid, fix it or clear the file to abort:\n# ",
+		},
+		{
+			name: "text with the header kept",
+			newParser: func(input io.Reader) format.MessageParser {
+				return format.TextMessageParser(input, prototext.UnmarshalOptions{})
+			},
+			commentPrefix: "#",
+			msg:           "msg: \"hi\"\n",
+			// The user keeps a part of the header, which is a comment of the format, so it's skipped by the parser.
+			edits:      []string{"msg: hi\n", "# The request is invalid\n\nmsg: \"hello\"\n"},
+			want:       "# The request is invalid\n\nmsg: \"hello\"\n",
+			wantHeader: "# The request is invalid, fix it or clear the file to abort:\n# ",
+		},
+		{
+			name: "yaml cleared",
+			newParser: func(input io.Reader) format.MessageParser {
+				return format.YAMLMessageParser(input, protojson.UnmarshalOptions{})
+			},
+			commentPrefix: "#",
+			msg:           "msg: hi\n",
+			edits:         []string{"msg: [hi]\n", "# The request is invalid\n"},
+			wantHeader:    "# The request is invalid, fix it or clear the file to abort:\n# ",
+			wantErr:       usecase.ErrEmptyRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := &fakeEditor{edits: tt.edits}
+
+			got, err := usecase.EditRequest(e, tt.newParser, tt.commentPrefix, &testdata.EchoRequest{}, tt.msg)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+
+			require.Len(t, e.opened, 2)
+			require.Equal(t, tt.msg, e.opened[0])
+			require.True(t, strings.HasPrefix(e.opened[1], tt.wantHeader), e.opened[1])
+			require.True(t, strings.HasSuffix(e.opened[1], "\n\n"+tt.edits[0]), e.opened[1])
+		})
+	}
+}
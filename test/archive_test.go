@@ -0,0 +1,138 @@
+package test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+var archiveProtos = map[string]string{
+	"schema-v1/proto/echo/echo.proto": `syntax = "proto3";
+
+package echo;
+
+import "echo/messages.proto";
+
+service EchoService {
+  rpc Echo(EchoRequest) returns (EchoResponse) {}
+}
+`,
+	"schema-v1/proto/echo/messages.proto": `syntax = "proto3";
+
+package echo;
+
+message EchoRequest {
+  string msg = 1;
+}
+
+message EchoResponse {
+  string msg = 1;
+}
+`,
+}
+
+func TestArchiveImportPaths(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	zipFile, err := createTempFile(fs, "schema.zip", string(zipArchive(t, archiveProtos)))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+
+	tarFile, err := createTempFile(fs, "schema.tar.gz", string(tarGzArchive(t, archiveProtos)))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "zip",
+			args: []string{"-i", zipFile + "/schema-v1/proto", "-p", "echo/echo.proto"},
+		},
+		{
+			name: "tar.gz with glob",
+			args: []string{"-i", tarFile + "/schema-v1/proto", "-p", "echo/*.proto"},
+		},
+		{
+			name: "auto discovery",
+			args: []string{"-i", tarFile + "/schema-v1/proto", "--auto-discover"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := runCall(
+				fs,
+				nil,
+				append([]string{"-a", address(insecureSocket), "echo.EchoService.Echo", "-d", `{"msg":"archive"}`}, tt.args...)...,
+			)
+			if err != nil {
+				t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+			}
+
+			var got map[string]any
+			require.NoError(t, json.Unmarshal(b, &got))
+			require.Equal(t, map[string]any{"msg": "archive"}, got)
+		})
+	}
+
+	t.Run("missing archive", func(t *testing.T) {
+		b, err := run(fs, nil, "list", "-i", "missing.zip", "-p", "echo/echo.proto")
+		require.Error(t, err)
+		require.Contains(t, string(b), `failed to mount "missing.zip"`)
+	})
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w := zip.NewWriter(&buf)
+
+	for name, contents := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+
+		_, err = f.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func tarGzArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(contents)),
+			Typeflag: tar.TypeReg,
+		}))
+
+		_, err := tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	return buf.Bytes()
+}
@@ -0,0 +1,187 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufWorkspace(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	cacheDir := filepath.Join(wd, "bufcache")
+	t.Setenv("BUF_CACHE_DIR", cacheDir)
+
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	files := map[string]string{
+		"buf.yaml": `version: v2
+modules:
+  - path: bufproto
+    excludes:
+      - bufproto/broken
+      - bufproto/legacy
+`,
+		"buf.lock": `version: v2
+deps:
+  - name: buf.build/acme/messages
+    commit: 0123456789abcdef
+    digest: b5:0123456789abcdef
+`,
+		"bufproto/echo/echo.proto": `syntax = "proto3";
+
+package echo;
+
+import "acme/messages.proto";
+
+service EchoService {
+  rpc Echo(acme.EchoRequest) returns (acme.EchoResponse) {}
+}
+`,
+		"bufproto/broken/broken.proto": `syntax = "proto3";
+
+package broken;
+
+message Broken {
+  Unknown field = 1;
+}
+`,
+		"bufproto/legacy/legacy.proto": `syntax = "proto3";
+
+package legacy;
+
+service LegacyService {
+  rpc Echo(Message) returns (Message) {}
+}
+
+message Message {}
+`,
+		"bufcache/v3/modules/b5/buf.build/acme/messages/0123456789abcdef/files/acme/messages.proto": `syntax = "proto3";
+
+package acme;
+
+message EchoRequest {
+  string msg = 1;
+}
+
+message EchoResponse {
+  string msg = 1;
+}
+`,
+	}
+
+	for name, contents := range files {
+		name = filepath.Join(wd, name)
+
+		require.NoError(t, fs.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, afero.WriteFile(fs, name, []byte(contents), 0o644))
+	}
+
+	t.Run("call", func(t *testing.T) {
+		b, err := runCall(
+			fs,
+			nil,
+			"-a", address(insecureSocket),
+			"-p", "echo/echo.proto",
+			"echo.EchoService.Echo",
+			"-d", `{"msg":"buf"}`,
+		)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(b, &got))
+		require.Equal(t, map[string]any{"msg": "buf"}, got)
+	})
+
+	t.Run("call with path relative to working directory", func(t *testing.T) {
+		// The in-memory layer doesn't resolve relative paths against the working directory, so the file is copied.
+		name := "bufproto/echo/echo.proto"
+		require.NoError(t, fs.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, afero.WriteFile(fs, name, []byte(files[name]), 0o644))
+
+		b, err := runCall(
+			fs,
+			nil,
+			"-a", address(insecureSocket),
+			"-p", name,
+			"echo.EchoService.Echo",
+			"-d", `{"msg":"buf wd"}`,
+		)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(b, &got))
+		require.Equal(t, map[string]any{"msg": "buf wd"}, got)
+	})
+
+	t.Run("auto discovery skips excluded files", func(t *testing.T) {
+		b, err := run(fs, nil, "list", "--auto-discover")
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Equal(t, "echo.EchoService\n", string(b))
+	})
+
+	t.Run("glob skips excluded files", func(t *testing.T) {
+		b, err := run(fs, nil, "list", "-p", "**/*.proto")
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Equal(t, "echo.EchoService\n", string(b))
+	})
+
+	t.Run("explicit import paths ignore workspace", func(t *testing.T) {
+		b, err := run(fs, nil, "list", "-i", filepath.Join(wd, "bufproto/legacy"), "--auto-discover")
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Equal(t, "legacy.LegacyService\n", string(b))
+	})
+}
+
+func TestBufWorkspaceMalformed(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	files := map[string]string{
+		"buf.yaml": "version: [",
+		"bufplain/plain.proto": `syntax = "proto3";
+
+package plain;
+
+service PlainService {
+  rpc Echo(Message) returns (Message) {}
+}
+
+message Message {}
+`,
+	}
+
+	for name, contents := range files {
+		name = filepath.Join(wd, name)
+
+		require.NoError(t, fs.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, afero.WriteFile(fs, name, []byte(contents), 0o644))
+	}
+
+	b, err := run(fs, nil, "list", "-p", filepath.Join(wd, "bufplain/plain.proto"))
+	if err != nil {
+		t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+	}
+
+	require.Equal(t, "plain.PlainService\n", string(b))
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	internalproto "github.com/heartandu/easyrpc/internal/proto"
+)
+
+func TestCache(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	connArgs := []string{"-a", address(insecureSocket), "-r", "--cache-ttl", "1m"}
+	listArgs := append([]string{"list"}, connArgs...)
+	liveServices := "echo.EchoService\ngrpc.reflection.v1.ServerReflection\ngrpc.reflection.v1alpha.ServerReflection\n"
+
+	dir, err := internalproto.CacheDir()
+	require.NoError(t, err)
+
+	// Listing services doesn't fetch the whole schema, so the cache isn't filled.
+	b, err := run(fs, nil, listArgs...)
+	if err != nil {
+		t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+	}
+
+	require.Equal(t, liveServices, string(b))
+
+	exists, err := afero.DirExists(fs, dir)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// Completion lists methods, which fills the cache.
+	b, err = runCallAutocomplete(fs, append(connArgs, "")...)
+	if err != nil {
+		t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+	}
+
+	files, err := afero.ReadDir(fs, dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	cacheFile := filepath.Join(dir, files[0].Name())
+
+	// Replace the cached descriptors to tell them apart from the live ones.
+	cached, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("cached.proto"),
+			Package: proto.String("cached"),
+			Syntax:  proto.String("proto3"),
+			Service: []*descriptorpb.ServiceDescriptorProto{{Name: proto.String("Service")}},
+		}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, cacheFile, cached, 0o600))
+
+	t.Run("fresh cache", func(t *testing.T) {
+		b, err := run(fs, nil, listArgs...)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Equal(t, "cached.Service\n", string(b))
+	})
+
+	t.Run("fallback to reflection for missing symbols", func(t *testing.T) {
+		b, err := run(fs, nil, append([]string{"describe", "echo.EchoRequest"}, connArgs...)...)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Contains(t, string(b), "message EchoRequest")
+	})
+
+	t.Run("export ignores cache", func(t *testing.T) {
+		b, err := run(fs, nil, append([]string{"export"}, connArgs...)...)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		set := &descriptorpb.FileDescriptorSet{}
+		require.NoError(t, proto.Unmarshal(b, set))
+		require.NotEmpty(t, set.GetFile())
+
+		for _, f := range set.GetFile() {
+			require.NotEqual(t, "cached.proto", f.GetName())
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		b, err := run(fs, nil, "list", "-a", address(insecureSocket), "-r")
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Equal(t, liveServices, string(b))
+	})
+
+	t.Run("expired cache", func(t *testing.T) {
+		expired := time.Now().Add(-time.Hour)
+		require.NoError(t, fs.Chtimes(cacheFile, expired, expired))
+
+		b, err := run(fs, nil, listArgs...)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Equal(t, liveServices, string(b))
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		b, err := run(fs, nil, "cache", "clear")
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		exists, err := afero.DirExists(fs, dir)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallBidiConversation(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	in, inWriter := io.Pipe()
+	out, outWriter := io.Pipe()
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		err := runWithOutput(
+			fs,
+			in,
+			outWriter,
+			"call", "echo.EchoService.BidiStream", "-a", address(insecureSocket), "-r", "-d", "-",
+		)
+
+		// Unblock the test in case the command exits before consuming the whole input.
+		in.CloseWithError(errors.New("command exited"))
+		outWriter.Close()
+
+		errCh <- err
+	}()
+
+	responses := json.NewDecoder(out)
+
+	// Every next message is sent only after the response to the previous one is received,
+	// which is only possible when responses are printed while requests are still being sent.
+	for i := range 3 {
+		msg := fmt.Sprintf("message %d", i)
+
+		_, err := fmt.Fprintf(inWriter, `{"msg":%q}`, msg)
+		require.NoError(t, err)
+
+		got := map[string]any{}
+		decoded := make(chan error, 1)
+
+		go func() { decoded <- responses.Decode(&got) }()
+
+		select {
+		case err := <-decoded:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("response to %q is not received", msg)
+		}
+
+		require.Equal(t, map[string]any{"msg": msg}, got)
+	}
+
+	require.NoError(t, inWriter.Close())
+
+	_, err := io.ReadAll(out)
+	require.NoError(t, err)
+	require.NoError(t, <-errCh)
+}
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heartandu/easyrpc/internal/cmds"
+)
+
+func TestCallEdit(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+	protoArgs := []string{"-a", address(insecureSocket), "-i", importPath, "-p", protoFile, "-e"}
+
+	// The editor leaves the request as is, so the skeleton or the data is sent unchanged.
+	t.Setenv("EDITOR", "true")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "skeleton",
+			args: []string{"echo.EchoService.Echo"},
+			want: `{"msg": ""}`,
+		},
+		{
+			name: "data",
+			args: []string{"echo.EchoService.Echo", "-d", `{"msg": "edited"} // comment`},
+			want: `{"msg": "edited"}`,
+		},
+		{
+			name: "yaml data",
+			args: []string{"echo.EchoService.Echo", "--input-format", "yaml", "-d", "msg: edited # comment\n"},
+			want: `{"msg": "edited"}`,
+		},
+		{
+			name: "text data",
+			args: []string{"echo.EchoService.Echo", "--input-format", "text", "-d", "# comment\nmsg: \"edited\"\n"},
+			want: `{"msg": "edited"}`,
+		},
+		{
+			name: "client stream",
+			args: []string{"echo.EchoService.ClientStream", "-d", `{"msg": "1"}{"msg": "2"}`, "-H", "test=3"},
+			want: `{"msgs": ["1", "2", "3"]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := runCall(fs, nil, append(tt.args, protoArgs...)...)
+			if err != nil {
+				t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+			}
+
+			require.JSONEq(t, tt.want, string(b))
+		})
+	}
+
+	t.Run("editor error", func(t *testing.T) {
+		t.Setenv("EDITOR", "false")
+
+		_, err := runCall(fs, nil, append([]string{"echo.EchoService.Echo"}, protoArgs...)...)
+		require.Error(t, err)
+	})
+
+	t.Run("binary input", func(t *testing.T) {
+		args := append([]string{"echo.EchoService.Echo", "--input-format", "binary"}, protoArgs...)
+
+		_, err := runCall(fs, nil, args...)
+		require.ErrorIs(t, err, cmds.ErrEditBinaryInput)
+	})
+
+	for _, inputFormat := range []string{"yaml", "text"} {
+		t.Run(inputFormat+" input without data", func(t *testing.T) {
+			// The editor must not be opened, so it would fail otherwise.
+			t.Setenv("EDITOR", "false")
+
+			args := append([]string{"echo.EchoService.Echo", "--input-format", inputFormat}, protoArgs...)
+
+			_, err := runCall(fs, nil, args...)
+			require.ErrorIs(t, err, cmds.ErrEditEmptyInput)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/prototext"
+	"gopkg.in/yaml.v3"
+
+	"github.com/heartandu/easyrpc/internal/app"
+	"github.com/heartandu/easyrpc/internal/testdata"
+)
+
+func TestCallFormat(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	yamlConfigFileName, err := createTempFile(fs, "format.yaml", `
+        address: `+address(insecureSocket)+`
+        reflection: true
+        format: yaml`)
+	if err != nil {
+		t.Fatalf("failed to create format config file: %v", err)
+	}
+
+	streamArgs := []string{
+		"echo.EchoService.ServerStream",
+		"-a",
+		address(insecureSocket),
+		"-r",
+		"-d",
+		`{"msgs":["1","2","3"]}`,
+	}
+	want := []string{"1", "2", "3"}
+
+	tests := []struct {
+		name   string
+		args   []string
+		decode func(t *testing.T, b []byte) []string
+	}{
+		{
+			name:   "compact",
+			args:   append([]string{"--format", "compact"}, streamArgs...),
+			decode: decodeNDJSON,
+		},
+		{
+			name:   "ndjson",
+			args:   append([]string{"--format", "ndjson"}, streamArgs...),
+			decode: decodeNDJSON,
+		},
+		{
+			name: "text",
+			args: append([]string{"--format", "text"}, streamArgs...),
+			decode: func(t *testing.T, b []byte) []string {
+				t.Helper()
+
+				var msgs []string
+
+				for _, record := range bytes.Split(bytes.TrimSpace(b), []byte("\n\n")) {
+					msg := &testdata.ServerStreamResponse{}
+					require.NoError(t, prototext.Unmarshal(record, msg))
+
+					msgs = append(msgs, msg.GetMsg())
+				}
+
+				return msgs
+			},
+		},
+		{
+			name:   "yaml",
+			args:   append([]string{"--format", "yaml"}, streamArgs...),
+			decode: decodeYAML,
+		},
+		{
+			name:   "yaml config",
+			args:   []string{"echo.EchoService.ServerStream", "--config", yamlConfigFileName, "-d", `{"msgs":["1","2","3"]}`},
+			decode: decodeYAML,
+		},
+		{
+			name: "binary",
+			args: append([]string{"--format", "binary"}, streamArgs...),
+			decode: func(t *testing.T, b []byte) []string {
+				t.Helper()
+
+				var msgs []string
+
+				r := bytes.NewReader(b)
+
+				for {
+					msg := &testdata.ServerStreamResponse{}
+					if err := protodelim.UnmarshalFrom(r, msg); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+
+						t.Fatalf("failed to decode output: %v", err)
+					}
+
+					msgs = append(msgs, msg.GetMsg())
+				}
+
+				return msgs
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := runCall(fs, nil, tt.args...)
+			if err != nil {
+				t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+			}
+
+			require.Equal(t, want, tt.decode(t, b))
+		})
+	}
+}
+
+func TestCallUnknownFormat(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	b, err := runCall(fs, nil, "echo.EchoService.Echo", "-a", address(insecureSocket), "-r", "--format", "xml")
+	require.Equal(t, app.ExitCodeValidation, app.ExitCode(err), "output = %s", string(b))
+	require.Contains(t, string(b), `unknown format "xml"`)
+}
+
+func decodeNDJSON(t *testing.T, b []byte) []string {
+	t.Helper()
+
+	var msgs []string
+
+	for _, line := range bytes.Split(bytes.TrimSpace(b), []byte("\n")) {
+		msg := map[string]string{}
+		require.NoError(t, json.Unmarshal(line, &msg), "line is not a JSON object: %s", line)
+
+		msgs = append(msgs, msg["msg"])
+	}
+
+	return msgs
+}
+
+func decodeYAML(t *testing.T, b []byte) []string {
+	t.Helper()
+
+	var msgs []string
+
+	d := yaml.NewDecoder(bytes.NewReader(b))
+
+	for {
+		msg := map[string]string{}
+		if err := d.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			t.Fatalf("failed to decode output: %v", err)
+		}
+
+		msgs = append(msgs, msg["msg"])
+	}
+
+	return msgs
+}
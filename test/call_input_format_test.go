@@ -0,0 +1,89 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	"github.com/heartandu/easyrpc/internal/app"
+	"github.com/heartandu/easyrpc/internal/testdata"
+)
+
+func TestCallInputFormat(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	var binaryInput bytes.Buffer
+
+	for _, msg := range []string{"1", "2", "3"} {
+		if _, err := protodelim.MarshalTo(&binaryInput, &testdata.ClientStreamRequest{Msg: msg}); err != nil {
+			t.Fatalf("failed to marshal binary input: %v", err)
+		}
+	}
+
+	binaryFileName, err := createTempFile(fs, "msgs.bin", binaryInput.String())
+	if err != nil {
+		t.Fatalf("failed to create binary input file: %v", err)
+	}
+
+	yamlConfigFileName, err := createTempFile(fs, "input_format.yaml", `
+        address: `+address(insecureSocket)+`
+        reflection: true
+        input_format: yaml`)
+	if err != nil {
+		t.Fatalf("failed to create input format config file: %v", err)
+	}
+
+	streamArgs := []string{"echo.EchoService.ClientStream", "-a", address(insecureSocket), "-r"}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "yaml",
+			args: append([]string{"--input-format", "yaml", "-d", "msg: '1'\n---\nmsg: '2'\n---\nmsg: '3'\n"}, streamArgs...),
+		},
+		{
+			name: "yaml config",
+			args: []string{
+				"echo.EchoService.ClientStream",
+				"--config",
+				yamlConfigFileName,
+				"-d",
+				"---\nmsg: \"1\"\n---\nmsg: \"2\"\n---\nmsg: \"3\"",
+			},
+		},
+		{
+			name: "text",
+			args: append([]string{"--input-format", "text", "-d", "msg: \"1\"\n\nmsg: \"2\"\n\nmsg: \"3\""}, streamArgs...),
+		},
+		{
+			name: "binary",
+			args: append([]string{"--input-format", "binary", "-d", "@" + binaryFileName}, streamArgs...),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := runCall(fs, nil, tt.args...)
+			if err != nil {
+				t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+			}
+
+			got := map[string]any{}
+			require.NoError(t, json.Unmarshal(b, &got))
+			require.Equal(t, map[string]any{"msgs": []any{"1", "2", "3"}}, got)
+		})
+	}
+}
+
+func TestCallUnknownInputFormat(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	b, err := runCall(fs, nil, "echo.EchoService.Echo", "-a", address(insecureSocket), "-r", "--input-format", "xml")
+	require.Equal(t, app.ExitCodeValidation, app.ExitCode(err), "output = %s", string(b))
+	require.Contains(t, string(b), `unknown format "xml"`)
+}
@@ -0,0 +1,80 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heartandu/easyrpc/internal/app"
+)
+
+func TestCallJSONOptions(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	optionsConfigFileName, err := createTempFile(fs, "json_options.yaml", `
+        address: `+address(insecureSocket)+`
+        reflection: true
+        emit_unpopulated: false
+        discard_unknown: true`)
+	if err != nil {
+		t.Fatalf("failed to create json options config file: %v", err)
+	}
+
+	echoArgs := []string{"echo.EchoService.Echo", "-a", address(insecureSocket), "-r", "--format", "compact"}
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			name:     "emit unpopulated by default",
+			args:     echoArgs,
+			wantCode: app.ExitCodeOK,
+			want:     `{"msg":""}` + "\n",
+		},
+		{
+			name:     "do not emit unpopulated",
+			args:     append([]string{"--emit-unpopulated=false"}, echoArgs...),
+			wantCode: app.ExitCodeOK,
+			want:     "{}\n",
+		},
+		{
+			name:     "fail on unknown fields by default",
+			args:     append([]string{"-d", `{"msg":"hi","unknown":1}`}, echoArgs...),
+			wantCode: app.ExitCodeInvalidInput,
+		},
+		{
+			name:     "discard unknown fields",
+			args:     append([]string{"--discard-unknown", "-d", `{"msg":"hi","unknown":1}`}, echoArgs...),
+			wantCode: app.ExitCodeOK,
+			want:     `{"msg":"hi"}` + "\n",
+		},
+		{
+			name: "config",
+			args: []string{
+				"echo.EchoService.Echo",
+				"--config",
+				optionsConfigFileName,
+				"--format",
+				"compact",
+				"-d",
+				`{"unknown":1}`,
+			},
+			wantCode: app.ExitCodeOK,
+			want:     "{}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := runCall(fs, nil, tt.args...)
+			require.Equal(t, tt.wantCode, app.ExitCode(err), "output = %s", string(b))
+
+			if tt.want != "" {
+				require.Equal(t, tt.want, string(b))
+			}
+		})
+	}
+}
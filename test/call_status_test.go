@@ -0,0 +1,81 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallStatus(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains []string
+	}{
+		{
+			name: "without details",
+			args: []string{
+				"echo.EchoService.Error",
+				"-a",
+				address(insecureSocket),
+				"-r",
+			},
+			contains: []string{
+				"Status code: Internal\n",
+				"Status message: internal error\n",
+			},
+		},
+		{
+			name: "with details by reflection",
+			args: []string{
+				"echo.EchoService.Error",
+				"-a",
+				address(insecureSocket),
+				"-r",
+				"-d",
+				`{"msg":"must not be empty"}`,
+			},
+			contains: []string{
+				"Status code: InvalidArgument\n",
+				"Status message: invalid argument\n",
+				"Status details:\n",
+				`"type.googleapis.com/google.rpc.BadRequest"`,
+				`"must not be empty"`,
+				`"type.googleapis.com/echo.ErrorResponse"`,
+				`"custom detail"`,
+			},
+		},
+		{
+			name: "with details by proto",
+			args: []string{
+				"echo.EchoService.Error",
+				"-a",
+				address(insecureSocket),
+				"-i",
+				importPath,
+				"-p",
+				protoFile,
+				"-d",
+				`{"msg":"must not be empty"}`,
+			},
+			contains: []string{
+				"Status code: InvalidArgument\n",
+				`"type.googleapis.com/google.rpc.BadRequest"`,
+				`"type.googleapis.com/echo.ErrorResponse"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := runCall(fs, nil, tt.args...)
+			require.Error(t, err)
+
+			for _, want := range tt.contains {
+				require.Contains(t, string(b), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heartandu/easyrpc/internal/app"
+)
+
+func TestCallTimeout(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	// The listener accepts connections, but never speaks HTTP/2, so connections are never ready.
+	lis, err := net.Listen(protocol, "localhost:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer lis.Close()
+
+	timeoutConfigFileName, err := createTempFile(fs, "timeout.yaml", `
+        address: `+address(insecureSocket)+`
+        reflection: true
+        timeout: 100ms`)
+	if err != nil {
+		t.Fatalf("failed to create timeout config file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		contains []string
+	}{
+		{
+			name: "completes within timeout",
+			args: []string{
+				"echo.EchoService.Echo",
+				"-a",
+				address(insecureSocket),
+				"-r",
+				"--timeout",
+				"5s",
+				"--connect-timeout",
+				"5s",
+				"-H",
+				"sleep=10ms",
+			},
+			wantCode: app.ExitCodeOK,
+		},
+		{
+			name: "timeout flag",
+			args: []string{
+				"echo.EchoService.Echo",
+				"-a",
+				address(insecureSocket),
+				"-r",
+				"--timeout",
+				"100ms",
+				"-H",
+				"sleep=5s",
+			},
+			wantCode: app.ExitCodeStatusBase + 4,
+			contains: []string{
+				"Status code: DeadlineExceeded\n",
+				"call didn't complete within 100ms",
+			},
+		},
+		{
+			name: "timeout config",
+			args: []string{
+				"echo.EchoService.Echo",
+				"--config",
+				timeoutConfigFileName,
+				"-H",
+				"sleep=5s",
+			},
+			wantCode: app.ExitCodeStatusBase + 4,
+			contains: []string{
+				"call didn't complete within 100ms",
+			},
+		},
+		{
+			name: "connect timeout",
+			args: []string{
+				"echo.EchoService.Echo",
+				"-a",
+				lis.Addr().String(),
+				"-r",
+				"--connect-timeout",
+				"100ms",
+			},
+			wantCode: app.ExitCodeStatusBase + 4,
+			contains: []string{
+				"within 100ms",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := runCall(fs, nil, tt.args...)
+			require.Equal(t, tt.wantCode, app.ExitCode(err), "output = %s", string(b))
+
+			for _, want := range tt.contains {
+				require.Contains(t, string(b), want)
+			}
+		})
+	}
+}
+
+func TestCallInterrupt(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	in, inWriter := io.Pipe()
+	defer inWriter.Close()
+
+	go func() {
+		if _, err := inWriter.Write([]byte(`{"msg":"before interrupt"}`)); err != nil {
+			return
+		}
+
+		// Give the command enough time to start handling interrupts and send the message,
+		// while the input stays open.
+		time.Sleep(500 * time.Millisecond)
+
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+			t.Errorf("failed to send interrupt: %v", err)
+		}
+	}()
+
+	b, err := runCall(fs, in, "echo.EchoService.ClientStream", "-a", address(insecureSocket), "-r", "-d", "-")
+	if err != nil {
+		t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+	}
+
+	got := map[string]any{}
+	require.NoError(t, json.NewDecoder(bytes.NewReader(b)).Decode(&got))
+	require.Equal(t, map[string]any{"msgs": []any{"before interrupt"}}, got)
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallVerbose(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains []string
+	}{
+		{
+			name: "unary",
+			args: []string{
+				"echo.EchoService.Echo",
+				"-a",
+				address(insecureSocket),
+				"-r",
+				"-v",
+				"-d",
+				`{"msg":"verbose"}`,
+			},
+			contains: []string{
+				"Response headers:\n",
+				"  x-test-header: echo header\n",
+				"Response trailers:\n  x-test-trailer: echo trailer\n",
+				"Status code: OK\n",
+				`"verbose"`,
+			},
+		},
+		{
+			name: "server streaming",
+			args: []string{
+				"echo.EchoService.ServerStream",
+				"-a",
+				address(insecureSocket),
+				"-r",
+				"-v",
+				"-d",
+				`{"msgs":["1","2"]}`,
+			},
+			contains: []string{
+				"  x-test-header: stream header\n",
+				"Response trailers:\n  x-test-trailer: stream trailer\n",
+				"Status code: OK\n",
+			},
+		},
+		{
+			name: "failed call",
+			args: []string{
+				"echo.EchoService.Error",
+				"-a",
+				address(insecureSocket),
+				"-r",
+				"-v",
+			},
+			contains: []string{
+				"Status code: Internal\n",
+				"Status message: internal error\n",
+			},
+		},
+		{
+			name: "web unary",
+			args: []string{
+				"echo.EchoService.Echo",
+				"-a",
+				address(insecureWebSocket),
+				"-w",
+				"-i",
+				importPath,
+				"-p",
+				protoFile,
+				"-v",
+				"-d",
+				`{"msg":"web verbose"}`,
+			},
+			contains: []string{
+				"Response headers:\n",
+				"  x-test-header: echo header\n",
+				"Response trailers:\n  x-test-trailer: echo trailer\n",
+				"Status code: OK\n",
+				`"web verbose"`,
+			},
+		},
+		{
+			name: "web server streaming",
+			args: []string{
+				"echo.EchoService.ServerStream",
+				"-a",
+				address(insecureWebSocket),
+				"-w",
+				"-i",
+				importPath,
+				"-p",
+				protoFile,
+				"-v",
+				"-d",
+				`{"msgs":["1","2"]}`,
+			},
+			contains: []string{
+				"  x-test-header: stream header\n",
+				"Response trailers:\n  x-test-trailer: stream trailer\n",
+				"Status code: OK\n",
+			},
+		},
+		{
+			name: "web failed call",
+			args: []string{
+				"echo.EchoService.Error",
+				"-a",
+				address(insecureWebSocket),
+				"-w",
+				"-i",
+				importPath,
+				"-p",
+				protoFile,
+				"-v",
+			},
+			contains: []string{
+				"Status code: Internal\n",
+				"Status message: internal error\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := runCall(fs, nil, tt.args...)
+
+			for _, want := range tt.contains {
+				require.Contains(t, string(b), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heartandu/easyrpc/pkg/descriptor"
+)
+
+func TestDescribe(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	_, err := createTempFile(fs, "describe.proto", `syntax = "proto3";
+
+package describe;
+
+// Greeter greets people.
+service Greeter {
+  // Greet returns a greeting.
+  rpc Greet(GreetRequest) returns (GreetResponse) {
+    option deprecated = true;
+  }
+}
+
+// GreetRequest is a request to greet somebody.
+message GreetRequest {
+  // Name of the person.
+  string name = 1;
+  Kind kind = 2;
+}
+
+message GreetResponse {
+  string greeting = 1;
+}
+
+// Kind is a kind of greeting.
+enum Kind {
+  KIND_UNSPECIFIED = 0;
+  KIND_FORMAL = 1;
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to create proto file: %v", err)
+	}
+
+	protoArgs := []string{"-i", ".", "-p", "describe.proto"}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "service",
+			args: append([]string{"describe.Greeter"}, protoArgs...),
+			want: `// Greeter greets people.
+service Greeter {
+  // Greet returns a greeting.
+  rpc Greet ( GreetRequest ) returns ( GreetResponse ) {
+    option deprecated = true;
+  }
+}
+`,
+		},
+		{
+			name: "method relative to package",
+			args: append([]string{"Greeter.Greet", "--package", "describe"}, protoArgs...),
+			want: `// Greet returns a greeting.
+rpc Greet ( GreetRequest ) returns ( GreetResponse ) {
+  option deprecated = true;
+}
+`,
+		},
+		{
+			name: "message",
+			args: append([]string{"describe.GreetRequest"}, protoArgs...),
+			want: `// GreetRequest is a request to greet somebody.
+message GreetRequest {
+  // Name of the person.
+  string name = 1;
+
+  Kind kind = 2;
+}
+`,
+		},
+		{
+			name: "enum",
+			args: append([]string{"Kind", "--package", "describe"}, protoArgs...),
+			want: `// Kind is a kind of greeting.
+enum Kind {
+  KIND_UNSPECIFIED = 0;
+
+  KIND_FORMAL = 1;
+}
+`,
+		},
+		{
+			name: "fully qualified name with package from reflection",
+			args: []string{"echo.EchoRequest", "--package", "echo", "-a", address(insecureSocket), "-r"},
+			want: `message EchoRequest {
+  string msg = 1;
+}
+`,
+		},
+		{
+			name: "message from reflection",
+			args: []string{"echo.EchoRequest", "-a", address(insecureSocket), "-r"},
+			want: `message EchoRequest {
+  string msg = 1;
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := run(fs, nil, append([]string{"describe"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+			}
+
+			require.Equal(t, tt.want, string(b))
+		})
+	}
+}
+
+func TestDescribeUnknownSymbol(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	b, err := run(fs, nil, "describe", "echo.Unknown", "-i", importPath, "-p", protoFile)
+	require.ErrorIs(t, err, descriptor.ErrSymbolNotFound)
+	require.Contains(t, string(b), `symbol not found: "echo.Unknown"`)
+}
+
+func TestDescribeAutocomplete(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "partial completion",
+			args: []string{"-i", importPath, "-p", protoFile, "echo.echore"},
+			want: []string{
+				"echo.EchoRequest",
+				"echo.EchoResponse",
+			},
+		},
+		{
+			name: "partial completion relative to package",
+			args: []string{"-r", "-a", address(insecureSocket), "--package", "echo", "Bidi"},
+			want: []string{
+				"EchoService.BidiStream",
+				"BidiStreamRequest",
+				"BidiStreamResponse",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := run(fs, nil, append([]string{"__complete", "describe"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+			if len(lines) < 2 {
+				t.Fatalf("autocomplete returned unknown response: %v", lines)
+			}
+
+			require.Equal(t, tt.want, lines[:len(lines)-2])
+		})
+	}
+}
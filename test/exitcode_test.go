@@ -0,0 +1,97 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heartandu/easyrpc/internal/app"
+)
+
+func TestExitCode(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	brokenProto, err := createTempFile(fs, "broken.proto", `syntax = "proto3"; message {`)
+	if err != nil {
+		t.Fatalf("failed to create broken proto file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{
+			name: "success",
+			args: []string{"echo.EchoService.Echo", "-a", address(insecureSocket), "-r"},
+			want: app.ExitCodeOK,
+		},
+		{
+			name: "missing method",
+			args: []string{"-a", address(insecureSocket), "-r"},
+			want: app.ExitCodeValidation,
+		},
+		{
+			name: "invalid config",
+			args: []string{"echo.EchoService.Echo", "-r"},
+			want: app.ExitCodeValidation,
+		},
+		{
+			name: "unknown flag",
+			args: []string{"echo.EchoService.Echo", "-a", address(insecureSocket), "-r", "--unknown"},
+			want: app.ExitCodeValidation,
+		},
+		{
+			name: "invalid flag value",
+			args: []string{"echo.EchoService.Echo", "-a", address(insecureSocket), "-r", "--timeout", "soon"},
+			want: app.ExitCodeValidation,
+		},
+		{
+			name: "proto compilation failure",
+			args: []string{"echo.EchoService.Echo", "-a", address(insecureSocket), "-p", brokenProto},
+			want: app.ExitCodeCompilation,
+		},
+		{
+			name: "malformed json",
+			args: []string{"echo.EchoService.Echo", "-a", address(insecureSocket), "-r", "-d", `{"msg":`},
+			want: app.ExitCodeInvalidInput,
+		},
+		{
+			name: "unknown field",
+			args: []string{"echo.EchoService.Echo", "-a", address(insecureSocket), "-r", "-d", `{"unknown":1}`},
+			want: app.ExitCodeInvalidInput,
+		},
+		{
+			name: "internal status",
+			args: []string{"echo.EchoService.Error", "-a", address(insecureSocket), "-r"},
+			want: app.ExitCodeStatusBase + 13,
+		},
+		{
+			name: "invalid argument status",
+			args: []string{"echo.EchoService.Error", "-a", address(insecureSocket), "-r", "-d", `{"msg":"bad"}`},
+			want: app.ExitCodeStatusBase + 3,
+		},
+		{
+			name: "unimplemented method",
+			args: []string{"echo.EchoService.Missing", "-a", address(insecureSocket), "-r"},
+			want: app.ExitCodeError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runCall(fs, nil, tt.args...)
+			require.Equal(t, tt.want, app.ExitCode(err))
+		})
+	}
+
+	t.Run("describe with unavailable server", func(t *testing.T) {
+		_, err := run(fs, nil, "describe", "echo.EchoRequest", "-a", "localhost:1", "-r")
+		require.Equal(t, app.ExitCodeStatusBase+14, app.ExitCode(err))
+	})
+
+	t.Run("unexpected arguments", func(t *testing.T) {
+		_, err := run(fs, nil, "describe", "echo.EchoService", "echo.EchoService.Echo", "-a", address(insecureSocket), "-r")
+		require.Equal(t, app.ExitCodeValidation, app.ExitCode(err))
+	})
+}
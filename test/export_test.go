@@ -0,0 +1,62 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	reflectionArgs := []string{"-a", address(insecureSocket), "-r"}
+
+	t.Run("protoset", func(t *testing.T) {
+		b, err := run(fs, nil, append([]string{"export", "-o", "export.binpb"}, reflectionArgs...)...)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		b, err = runCall(
+			fs,
+			nil,
+			"-a", address(insecureSocket),
+			"--protoset", "export.binpb",
+			"echo.EchoService.Echo",
+			"-d", `{"msg":"exported"}`,
+		)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(b, &got))
+		require.Equal(t, map[string]any{"msg": "exported"}, got)
+	})
+
+	t.Run("proto files", func(t *testing.T) {
+		b, err := run(fs, nil, append([]string{"export", "--proto-dir", "exported"}, reflectionArgs...)...)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		b, err = run(fs, nil, "list", "-i", "exported", "-p", "test.proto", "-p", "grpc/reflection/v1/reflection.proto")
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Equal(t, "echo.EchoService\ngrpc.reflection.v1.ServerReflection\n", string(b))
+	})
+
+	t.Run("output and proto dir", func(t *testing.T) {
+		b, err := run(
+			fs,
+			nil,
+			append([]string{"export", "-o", "export.binpb", "--proto-dir", "exported"}, reflectionArgs...)...,
+		)
+		require.Error(t, err)
+		require.Contains(t, string(b), "none of the others can be")
+	})
+}
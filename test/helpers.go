@@ -26,22 +26,26 @@ func createTempFile(fs afero.Fs, name, contents string) (string, error) {
 }
 
 func run(fs afero.Fs, input io.Reader, args ...string) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+
+	err := runWithOutput(fs, input, buf, args...)
+
+	return buf.Bytes(), err
+}
+
+func runWithOutput(fs afero.Fs, input io.Reader, output io.Writer, args ...string) error {
 	oldArgs := os.Args
 	defer func() { os.Args = oldArgs }()
 
 	os.Args = append([]string{"easyrpc"}, args...)
 
-	buf := bytes.NewBuffer(nil)
-
 	a := app.NewApp("0.0.0-SNAPSHOT")
-	a.SetOutput(buf)
+	a.SetOutput(output)
 	a.SetFs(fs)
 
 	if input != nil {
 		a.SetInput(input)
 	}
 
-	err := a.Run()
-
-	return buf.Bytes(), err
+	return a.Run()
 }
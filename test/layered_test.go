@@ -0,0 +1,145 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayered(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	// The local checkout lags behind the server: it lacks most of the echo service methods,
+	// but has a service the server doesn't provide.
+	_, err := createTempFile(fs, "echo_old.proto", `syntax = "proto3";
+
+package echo;
+
+service EchoService {
+  rpc Echo(EchoRequest) returns (EchoResponse) {}
+}
+
+message EchoRequest {
+  string msg = 1;
+}
+
+message EchoResponse {
+  string msg = 1;
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to create proto file: %v", err)
+	}
+
+	_, err = createTempFile(fs, "local.proto", `syntax = "proto3";
+
+package local;
+
+service LocalService {
+  rpc Ping(Empty) returns (Empty) {}
+}
+
+message Empty {}
+`)
+	if err != nil {
+		t.Fatalf("failed to create proto file: %v", err)
+	}
+
+	sourceArgs := []string{"-a", address(insecureSocket), "-i", ".", "-p", "echo_old.proto", "-p", "local.proto"}
+
+	t.Run("call missing locally", func(t *testing.T) {
+		b, err := runCall(
+			fs,
+			nil,
+			append([]string{"--layered", "local-first", "echo.EchoService.Error", "-d", `{}`}, sourceArgs...)...,
+		)
+		require.Error(t, err)
+		require.Contains(t, string(b), "code: Internal")
+	})
+
+	t.Run("call found locally", func(t *testing.T) {
+		b, err := runCall(
+			fs,
+			nil,
+			append([]string{"--layered", "local-first", "echo.EchoService.Echo", "-d", `{"msg":"layered"}`}, sourceArgs...)...,
+		)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(b, &got))
+		require.Equal(t, map[string]any{"msg": "layered"}, got)
+	})
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "services of all sources",
+			args: []string{"--layered", "local-first"},
+			want: `echo.EchoService
+local.LocalService
+grpc.reflection.v1.ServerReflection
+grpc.reflection.v1alpha.ServerReflection
+`,
+		},
+		{
+			name: "local service first",
+			args: []string{"--layered", "local-first", "echo.EchoService"},
+			want: "echo.EchoService.Echo\n",
+		},
+		{
+			name: "reflection service first",
+			args: []string{"--layered", "reflection-first", "echo.EchoService"},
+			want: `echo.EchoService.Echo
+echo.EchoService.Error
+echo.EchoService.ClientStream
+echo.EchoService.ServerStream
+echo.EchoService.BidiStream
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := run(fs, nil, append(append([]string{"list"}, tt.args...), sourceArgs...)...)
+			if err != nil {
+				t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+			}
+
+			require.Equal(t, tt.want, string(b))
+		})
+	}
+}
+
+func TestLayeredValidation(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "unknown order",
+			args: []string{"--layered", "unknown", "-i", importPath, "-p", protoFile},
+			want: `unknown layered source order "unknown"`,
+		},
+		{
+			name: "no local source",
+			args: []string{"--layered", "local-first", "-r"},
+			want: "proto files, protosets or auto discovery must be used for the layered source",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := run(fs, nil, append([]string{"list", "-a", address(insecureSocket)}, tt.args...)...)
+			require.Error(t, err)
+			require.Contains(t, string(b), tt.want)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	protoArgs := []string{"-i", importPath, "-p", protoFile}
+	reflectionArgs := []string{"-a", address(insecureSocket), "-r"}
+
+	methods := `echo.EchoService.Echo
+echo.EchoService.Error
+echo.EchoService.ClientStream
+echo.EchoService.ServerStream
+echo.EchoService.BidiStream
+`
+	longMethods := `rpc Echo(echo.EchoRequest) returns (echo.EchoResponse)
+rpc Error(echo.ErrorRequest) returns (echo.ErrorResponse)
+rpc ClientStream(stream echo.ClientStreamRequest) returns (echo.ClientStreamResponse)
+rpc ServerStream(echo.ServerStreamRequest) returns (stream echo.ServerStreamResponse)
+rpc BidiStream(stream echo.BidiStreamRequest) returns (stream echo.BidiStreamResponse)
+`
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "services from proto files",
+			args: protoArgs,
+			want: "echo.EchoService\n",
+		},
+		{
+			name: "services of package from reflection",
+			args: append([]string{"--package", "echo"}, reflectionArgs...),
+			want: "echo.EchoService\n",
+		},
+		{
+			name: "services of unknown package",
+			args: append([]string{"--package", "unknown"}, reflectionArgs...),
+			want: "",
+		},
+		{
+			name: "methods of fully qualified service",
+			args: append([]string{"echo.EchoService"}, protoArgs...),
+			want: methods,
+		},
+		{
+			name: "methods of service relative to package",
+			args: append([]string{"EchoService", "--package", "echo"}, reflectionArgs...),
+			want: methods,
+		},
+		{
+			name: "methods of default service",
+			args: append([]string{"--package", "echo", "--service", "EchoService"}, reflectionArgs...),
+			want: methods,
+		},
+		{
+			name: "long methods",
+			args: append([]string{"echo.EchoService", "-l"}, reflectionArgs...),
+			want: longMethods,
+		},
+		{
+			name: "long services",
+			args: append([]string{"--long"}, protoArgs...),
+			want: "echo.EchoService\n" + indent(longMethods),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := run(fs, nil, append([]string{"list"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+			}
+
+			require.Equal(t, tt.want, string(b))
+		})
+	}
+}
+
+func TestListUnknownService(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	b, err := run(fs, nil, "list", "UnknownService", "-i", importPath, "-p", protoFile)
+	require.Error(t, err)
+	require.Contains(t, string(b), `service not found: "UnknownService"`)
+}
+
+func indent(s string) string {
+	lines := strings.SplitAfter(strings.TrimSuffix(s, "\n"), "\n")
+
+	return "  " + strings.Join(lines, "  ") + "\n"
+}
@@ -11,14 +11,17 @@ import (
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
 	"github.com/spf13/afero"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
 	"google.golang.org/grpc/status"
 
 	"github.com/heartandu/easyrpc/internal/testdata"
@@ -30,6 +33,8 @@ const (
 	tlsSocket         = ":50001"
 	insecureWebSocket = ":50002"
 	tlsWebSocket      = ":50003"
+	authSocket        = ":50004"
+	adminSocket       = ":50005"
 	protocol          = "tcp"
 
 	cacert = "../internal/testdata/rootCA.crt"
@@ -38,6 +43,12 @@ const (
 
 	importPath = "../internal/testdata"
 	protoFile  = "test.proto"
+
+	testHeaderKey  = "x-test-header"
+	testTrailerKey = "x-test-trailer"
+
+	authHeaderKey = "authorization"
+	authToken     = "Bearer token"
 )
 
 func TestMain(m *testing.M) {
@@ -69,6 +80,29 @@ func runTest(m *testing.M) int {
 		return 1
 	}
 
+	// The auth server requires the authorization header on every RPC, including reflection ones.
+	authServer := newServer(grpc.UnaryInterceptor(authUnaryInterceptor), grpc.StreamInterceptor(authStreamInterceptor))
+	defer authServer.Stop()
+
+	// The admin server only serves reflection of the auth server services.
+	adminServer := grpc.NewServer()
+	reflectionv1.RegisterServerReflectionServer(
+		adminServer,
+		reflection.NewServerV1(reflection.ServerOptions{Services: authServer}),
+	)
+
+	defer adminServer.Stop()
+
+	if err := serve(authServer, protocol, authSocket); err != nil {
+		log.Printf("failed to serve auth server: %v", err)
+		return 1
+	}
+
+	if err := serve(adminServer, protocol, adminSocket); err != nil {
+		log.Printf("failed to serve admin server: %v", err)
+		return 1
+	}
+
 	if err := serveWeb(grpcweb.WrapServer(insecureServer), protocol, insecureWebSocket, nil); err != nil {
 		log.Printf("failed to serve insecure web server: %v", err)
 		return 1
@@ -90,6 +124,37 @@ func newServer(opts ...grpc.ServerOption) *grpc.Server {
 	return s
 }
 
+func authUnaryInterceptor(
+	ctx context.Context,
+	req any,
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	if err := authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func authStreamInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := authorize(ss.Context()); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+func authorize(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if values := md.Get(authHeaderKey); len(values) == 0 || values[0] != authToken {
+		return status.Error(codes.Unauthenticated, "missing or invalid token")
+	}
+
+	return nil
+}
+
 func serve(s *grpc.Server, protocol, socket string) error {
 	lis, err := net.Listen(protocol, socket)
 	if err != nil {
@@ -149,17 +214,45 @@ type server struct {
 }
 
 func (s *server) Echo(ctx context.Context, r *testdata.EchoRequest) (*testdata.EchoResponse, error) {
+	if err := s.sleep(ctx); err != nil {
+		return nil, err
+	}
+
 	msg := r.GetMsg()
 
 	if testVal := s.getTestMDKey(ctx); testVal != "" {
 		msg += "\n" + testVal
 	}
 
+	if err := grpc.SetHeader(ctx, metadata.Pairs(testHeaderKey, "echo header")); err != nil {
+		return nil, fmt.Errorf("failed to set header: %w", err)
+	}
+
+	if err := grpc.SetTrailer(ctx, metadata.Pairs(testTrailerKey, "echo trailer")); err != nil {
+		return nil, fmt.Errorf("failed to set trailer: %w", err)
+	}
+
 	return &testdata.EchoResponse{Msg: msg}, nil
 }
 
 func (*server) Error(_ context.Context, r *testdata.ErrorRequest) (*testdata.ErrorResponse, error) {
-	return nil, status.Error(codes.Internal, "internal error")
+	if r.GetMsg() == "" {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	st, err := status.New(codes.InvalidArgument, "invalid argument").WithDetails(
+		&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "msg", Description: r.GetMsg()},
+			},
+		},
+		&testdata.ErrorResponse{Msg: "custom detail"},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add status details: %w", err)
+	}
+
+	return nil, st.Err()
 }
 
 func (s *server) ClientStream(
@@ -195,6 +288,12 @@ func (s *server) ServerStream(
 	r *testdata.ServerStreamRequest,
 	stream grpc.ServerStreamingServer[testdata.ServerStreamResponse],
 ) error {
+	if err := stream.SetHeader(metadata.Pairs(testHeaderKey, "stream header")); err != nil {
+		return fmt.Errorf("failed to set header: %w", err)
+	}
+
+	stream.SetTrailer(metadata.Pairs(testTrailerKey, "stream trailer"))
+
 	for _, msg := range r.GetMsgs() {
 		if err := stream.Send(&testdata.ServerStreamResponse{Msg: msg}); err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
@@ -213,8 +312,6 @@ func (s *server) ServerStream(
 func (s *server) BidiStream(
 	stream grpc.BidiStreamingServer[testdata.BidiStreamRequest, testdata.BidiStreamResponse],
 ) error {
-	var responses []*testdata.BidiStreamResponse
-
 	for {
 		r, err := stream.Recv()
 		if err != nil {
@@ -225,32 +322,51 @@ func (s *server) BidiStream(
 			return fmt.Errorf("failed to receive message: %w", err)
 		}
 
-		responses = append(responses, &testdata.BidiStreamResponse{Msg: r.GetMsg()})
+		if err := stream.Send(&testdata.BidiStreamResponse{Msg: r.GetMsg()}); err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
 	}
 
 	if testVal := s.getTestMDKey(stream.Context()); testVal != "" {
-		responses = append(responses, &testdata.BidiStreamResponse{Msg: testVal})
-	}
-
-	for _, resp := range responses {
-		if err := stream.Send(resp); err != nil {
-			return fmt.Errorf("failed to send message: %w", err)
+		if err := stream.Send(&testdata.BidiStreamResponse{Msg: testVal}); err != nil {
+			return fmt.Errorf("failed to send md message: %w", err)
 		}
 	}
 
 	return nil
 }
 
-func (*server) getTestMDKey(ctx context.Context) string {
+func (s *server) getTestMDKey(ctx context.Context) string {
 	const testMDKey = "test"
 
-	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(testMDKey)) != 0 {
-		return md.Get(testMDKey)[0]
+	return s.getMDKey(ctx, testMDKey)
+}
+
+func (*server) getMDKey(ctx context.Context, key string) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(key)) != 0 {
+		return md.Get(key)[0]
 	}
 
 	return ""
 }
 
+// sleep delays the response for the duration from the sleep metadata key, if it's set.
+func (s *server) sleep(ctx context.Context) error {
+	const sleepMDKey = "sleep"
+
+	d, err := time.ParseDuration(s.getMDKey(ctx, sleepMDKey))
+	if err != nil {
+		return nil
+	}
+
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
 func address(socket string) string {
 	return "localhost" + socket
 }
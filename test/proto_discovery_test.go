@@ -0,0 +1,100 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtoFilesDiscovery(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	require.NoError(t, fs.MkdirAll("protos/echo", 0o755))
+	require.NoError(t, fs.MkdirAll("protos/unrelated", 0o755))
+
+	_, err := createTempFile(fs, "protos/echo/echo.proto", `syntax = "proto3";
+
+package echo;
+
+import "echo/messages.proto";
+
+service EchoService {
+  rpc Echo(EchoRequest) returns (EchoResponse) {}
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to create proto file: %v", err)
+	}
+
+	_, err = createTempFile(fs, "protos/echo/messages.proto", `syntax = "proto3";
+
+package echo;
+
+message EchoRequest {
+  string msg = 1;
+}
+
+message EchoResponse {
+  string msg = 1;
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to create proto file: %v", err)
+	}
+
+	_, err = createTempFile(fs, "protos/unrelated/broken.proto", `syntax = "proto3";
+
+package unrelated;
+
+message Broken {
+  Unknown field = 1;
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to create proto file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "glob",
+			args: []string{"-i", "protos", "-p", "echo/*.proto"},
+		},
+		{
+			name: "auto discovery",
+			args: []string{"-i", "protos", "--auto-discover"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := runCall(
+				fs,
+				nil,
+				append([]string{"-a", address(insecureSocket), "echo.EchoService.Echo", "-d", `{"msg":"found"}`}, tt.args...)...,
+			)
+			if err != nil {
+				t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+			}
+
+			var got map[string]any
+			require.NoError(t, json.Unmarshal(b, &got))
+			require.Equal(t, map[string]any{"msg": "found"}, got)
+		})
+	}
+
+	t.Run("glob with broken file", func(t *testing.T) {
+		b, err := run(fs, nil, "list", "-i", "protos", "-p", "**/*.proto")
+		require.Error(t, err)
+		require.Contains(t, string(b), "failed to compile proto files")
+	})
+
+	t.Run("glob without matches", func(t *testing.T) {
+		b, err := run(fs, nil, "list", "-i", "protos", "-p", "missing/*.proto")
+		require.Error(t, err)
+		require.Contains(t, string(b), `no proto files match the pattern: "missing/*.proto"`)
+	})
+}
@@ -0,0 +1,91 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/heartandu/easyrpc/internal/testdata"
+)
+
+func TestProtoset(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(testdata.File_test_proto)},
+	}
+
+	b, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatalf("failed to marshal protoset: %v", err)
+	}
+
+	protoset, err := createTempFile(fs, "test.binpb", string(b))
+	if err != nil {
+		t.Fatalf("failed to create protoset file: %v", err)
+	}
+
+	conf, err := createTempFile(fs, "protoset.yaml", `
+        address: `+address(insecureSocket)+`
+        protosets:
+          - `+protoset)
+	if err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	t.Run("call", func(t *testing.T) {
+		b, err := runCall(
+			fs,
+			nil,
+			"-a", address(insecureSocket),
+			"--protoset", protoset,
+			"echo.EchoService.Echo",
+			"-d", `{"msg":"protoset"}`,
+		)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(b, &got))
+		require.Equal(t, map[string]any{"msg": "protoset"}, got)
+	})
+
+	t.Run("call with config", func(t *testing.T) {
+		b, err := runCall(fs, nil, "--config", conf, "echo.EchoService.Echo", "-d", `{"msg":"config"}`)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(b, &got))
+		require.Equal(t, map[string]any{"msg": "config"}, got)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		b, err := run(fs, nil, "list", "--protoset", protoset)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Equal(t, "echo.EchoService\n", string(b))
+	})
+}
+
+func TestProtosetInvalid(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	protoset, err := createTempFile(fs, "invalid.binpb", "not a protoset")
+	if err != nil {
+		t.Fatalf("failed to create protoset file: %v", err)
+	}
+
+	b, err := run(fs, nil, "list", "--protoset", protoset)
+	require.Error(t, err)
+	require.Contains(t, string(b), "invalid protoset files")
+}
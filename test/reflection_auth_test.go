@@ -0,0 +1,79 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReflectionMetadata(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	t.Run("missing metadata", func(t *testing.T) {
+		b, err := run(fs, nil, "list", "-a", address(authSocket), "-r")
+		require.Error(t, err)
+		require.Contains(t, string(b), "missing or invalid token")
+	})
+
+	t.Run("list with metadata", func(t *testing.T) {
+		b, err := run(fs, nil, "list", "-a", address(authSocket), "-r", "-H", authHeaderKey+"="+authToken)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Contains(t, string(b), "echo.EchoService\n")
+	})
+
+	t.Run("autocomplete with metadata", func(t *testing.T) {
+		b, err := runCallAutocomplete(
+			fs,
+			"-a", address(authSocket),
+			"-r",
+			"-H", authHeaderKey+"="+authToken,
+			"echo.EchoService.Ec",
+		)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Contains(t, string(b), "echo.EchoService.Echo\n")
+	})
+}
+
+func TestReflectionAddress(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	t.Run("call", func(t *testing.T) {
+		b, err := runCall(
+			fs,
+			nil,
+			"-a", address(authSocket),
+			"-r",
+			"--reflection-address", address(adminSocket),
+			"-H", authHeaderKey+"="+authToken,
+			"echo.EchoService.Echo",
+			"-d", `{"msg":"admin"}`,
+		)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(b, &got))
+		require.Equal(t, map[string]any{"msg": "admin"}, got)
+	})
+
+	t.Run("list without address", func(t *testing.T) {
+		b, err := run(fs, nil, "list", "-r", "--reflection-address", address(adminSocket))
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Equal(t, `echo.EchoService
+grpc.reflection.v1.ServerReflection
+grpc.reflection.v1alpha.ServerReflection
+`, string(b))
+	})
+}
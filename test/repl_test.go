@@ -0,0 +1,96 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heartandu/easyrpc/internal/cmds"
+)
+
+func TestRepl(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	tests := []struct {
+		name     string
+		args     []string
+		in       string
+		contains []string
+	}{
+		{
+			name: "call by proto",
+			args: []string{"-a", address(insecureSocket), "-i", importPath, "-p", protoFile},
+			in:   "call echo.EchoService.Echo {\"msg\":\"repl proto\"}\n",
+			contains: []string{
+				`"repl proto"`,
+			},
+		},
+		{
+			name: "multiple calls by reflection",
+			args: []string{"-a", address(insecureSocket), "-r"},
+			in: "call echo.EchoService.Echo {\"msg\":\"first\"}\n" +
+				"call echo.EchoService.Echo {\"msg\":\"second\"}\n",
+			contains: []string{
+				`"first"`,
+				`"second"`,
+			},
+		},
+		{
+			name: "package and service switching",
+			args: []string{"-a", address(insecureSocket), "-r"},
+			in: "package echo\n" +
+				"service EchoService\n" +
+				"call Echo {\"msg\":\"switched\"}\n" +
+				"list\n",
+			contains: []string{
+				`"switched"`,
+				"BidiStream\n",
+			},
+		},
+		{
+			name: "streaming call with metadata",
+			args: []string{"-a", address(insecureSocket), "-r", "-H", "test=md"},
+			in:   "call echo.EchoService.ClientStream {\"msg\":\"1\"}{\"msg\":\"2\"}\n",
+			contains: []string{
+				`"1"`,
+				`"2"`,
+				`"md"`,
+			},
+		},
+		{
+			name: "errors do not stop the session",
+			args: []string{"-a", address(insecureSocket), "-r"},
+			in: "unknown\n" +
+				"call echo.EchoService.Error {}\n" +
+				"call echo.EchoService.Echo {\"msg\":\"still alive\"}\n" +
+				"exit\n" +
+				"call echo.EchoService.Echo {\"msg\":\"unreachable\"}\n",
+			contains: []string{
+				`unknown command "unknown"`,
+				"internal error",
+				`"still alive"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := run(fs, strings.NewReader(tt.in), append([]string{"repl"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+			}
+
+			for _, want := range tt.contains {
+				require.Contains(t, string(b), want)
+			}
+
+			require.NotContains(t, string(b), "unreachable")
+		})
+	}
+
+	t.Run("non-json input format", func(t *testing.T) {
+		_, err := run(fs, strings.NewReader(""), "repl", "-a", address(insecureSocket), "-r", "--input-format", "yaml")
+		require.ErrorIs(t, err, cmds.ErrReplInputFormat)
+	})
+}
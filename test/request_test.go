@@ -0,0 +1,135 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest(t *testing.T) {
+	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
+
+	_, err := createTempFile(fs, "request.proto", `syntax = "proto3";
+
+package echo;
+
+service EchoService {
+  rpc Echo(EchoRequest) returns (EchoResponse) {}
+}
+
+enum Mode {
+  MODE_UNSPECIFIED = 0;
+  MODE_LOUD = 1;
+}
+
+message EchoRequest {
+  string msg = 1;
+  Mode mode = 2;
+  Options options = 3;
+  oneof target {
+    string user = 4;
+    string group = 5;
+  }
+}
+
+message Options {
+  repeated string tags = 1;
+  Options nested = 2;
+}
+
+message EchoResponse {
+  string msg = 1;
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to create proto file: %v", err)
+	}
+
+	protoArgs := []string{"-i", ".", "-p", "request.proto"}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "empty message",
+			args: []string{"echo.EchoService.Echo", "-i", importPath, "-p", protoFile},
+			want: `{
+  "msg": ""
+}
+`,
+		},
+		{
+			name: "depth limit",
+			args: append([]string{"echo.EchoService.Echo", "--depth", "2"}, protoArgs...),
+			want: `{
+  "msg": "",
+  "mode": "MODE_UNSPECIFIED",
+  "options": {
+    "tags": [
+      ""
+    ],
+    "nested": {}
+  },
+  "user": ""
+}
+`,
+		},
+		{
+			name: "from reflection",
+			args: []string{"EchoService.Echo", "--package", "echo", "-a", address(insecureSocket), "-r"},
+			want: `{
+  "msg": ""
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := run(fs, nil, append([]string{"request"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+			}
+
+			require.Equal(t, tt.want, string(b))
+		})
+	}
+
+	t.Run("edited skeleton is plain json", func(t *testing.T) {
+		// The editor leaves the annotated skeleton as is.
+		t.Setenv("EDITOR", "true")
+
+		b, err := run(fs, nil, append([]string{"request", "echo.EchoService.Echo", "--depth", "1", "-e"}, protoArgs...)...)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.Equal(t, `{
+  "msg": "",
+  "mode": "MODE_UNSPECIFIED",
+  "options": {},
+  "user": ""
+}
+`, string(b))
+	})
+
+	t.Run("skeleton as call input", func(t *testing.T) {
+		b, err := run(fs, nil, append([]string{"request", "echo.EchoService.Echo", "-o", "skeleton.json"}, protoArgs...)...)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		b, err = runCall(
+			fs,
+			nil,
+			append([]string{"-a", address(insecureSocket), "echo.EchoService.Echo", "-d", "@skeleton.json"}, protoArgs...)...,
+		)
+		if err != nil {
+			t.Fatalf("command failed: output = %v, err = %v", string(b), err)
+		}
+
+		require.JSONEq(t, `{"msg": ""}`, string(b))
+	})
+}